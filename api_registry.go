@@ -0,0 +1,50 @@
+package readme
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ApiRegistry allows interactions with the API registry, which stores uploaded API definitions
+type ApiRegistry interface {
+	Get(ctx context.Context, uuid string) (json.RawMessage, error)
+	Create(ctx context.Context, opt ApiRegistryCreateOptions) (*ApiRegistryEntry, error)
+}
+
+// ApiRegistryCreateOptions are the options available when registering an API definition
+type ApiRegistryCreateOptions struct {
+	SpecPath string
+}
+
+// ApiRegistryEntry is the result of registering an API definition
+type ApiRegistryEntry struct {
+	// RegistryUUID identifies the definition in the registry
+	RegistryUUID string          `json:"registryUUID"`
+	Definition   json.RawMessage `json:"definition"`
+}
+
+type api_registry struct {
+	client *Client
+}
+
+// Get the API definition with the specified registry UUID
+func (a *api_registry) Get(ctx context.Context, uuid string) (json.RawMessage, error) {
+	return getJSON[json.RawMessage](ctx, a.client, "api-registry/"+uuid, nil)
+}
+
+// Create registers the API definition at the specified path
+func (a *api_registry) Create(ctx context.Context, opt ApiRegistryCreateOptions) (*ApiRegistryEntry, error) {
+	body, header, err := a.client.newFileUploadBody(opt.SpecPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := a.client.do(ctx, "POST", "api-registry", body, header)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeJSON[*ApiRegistryEntry](a.client, response)
+}
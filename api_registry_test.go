@@ -0,0 +1,64 @@
+package readme
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApiRegistry_Get(t *testing.T) {
+	t.Run("returns the definition", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "GET", r.Method)
+			assert.Equal(t, "/api/v1/api-registry/abc123", r.URL.Path)
+			w.Write([]byte(`{"openapi":"3.0.0","info":{"title":"Pets"}}`))
+		}))
+
+		definition, err := client.ApiRegistry.Get(context.Background(), "abc123")
+
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"openapi":"3.0.0","info":{"title":"Pets"}}`, string(definition))
+	})
+
+	t.Run("reports missing definitions", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("content-type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"REGISTRY_NOTFOUND","message":"The registry entry couldn't be found.","docs":"https://docs.readme.com/logs/1"}`))
+		}))
+
+		_, err := client.ApiRegistry.Get(context.Background(), "missing")
+
+		responseError := &ResponseError{}
+		assert.ErrorAs(t, err, &responseError)
+		assert.Equal(t, "REGISTRY_NOTFOUND", responseError.ErrorCode)
+	})
+}
+
+func TestApiRegistry_Create(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.json")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(`{"openapi":"3.0.0"}`), 0644))
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/v1/api-registry", r.URL.Path)
+
+		file, header, err := r.FormFile("spec")
+		assert.Nil(t, err)
+		assert.Equal(t, "openapi.json", header.Filename)
+		data, _ := ioutil.ReadAll(file)
+		assert.Equal(t, `{"openapi":"3.0.0"}`, string(data))
+
+		writeJSON(w, map[string]interface{}{"registryUUID": "abc123", "definition": map[string]string{"openapi": "3.0.0"}})
+	}))
+
+	entry, err := client.ApiRegistry.Create(context.Background(), ApiRegistryCreateOptions{SpecPath: path})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "abc123", entry.RegistryUUID)
+	assert.JSONEq(t, `{"openapi":"3.0.0"}`, string(entry.Definition))
+}
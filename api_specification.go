@@ -61,38 +61,22 @@ type ApiSpecification struct {
 	Version    string                    `json:"version"`
 	LastSynced string                    `json:"lastSynced"`
 	Category   *ApiSpecificationCategory `json:"category"`
-	Type       string                    `json:"oas"`
+	Type       string                    `json:"type"`
 	ID         string                    `json:"id"`
 }
 
 // List the api specifications according to some paging options
 func (a *api_specification) List(ctx context.Context, version string, opt ApiSpecificationListOptions) (*ApiSpecificationList, error) {
-	url, err := addOptions("api-specification", opt)
-
-	if err != nil {
-		return nil, err
-	}
-
 	versionHeader := make(http.Header)
 	versionHeader.Add("x-readme-version", version)
 
-	response, err := a.client.do(ctx, "GET", url, nil, versionHeader)
-
-	if err != nil {
-		return nil, err
-	}
-
-	pagination, err := a.client.parsePaginationResponse(&response.Header)
+	items, pagination, err := list[ApiSpecification](ctx, a.client, "api-specification", opt, versionHeader)
 
 	if err != nil {
 		return nil, err
 	}
 
-	result := ApiSpecificationList{
-		Pagination: pagination,
-	}
-
-	return &result, a.client.decodeAndClose(response.Body, &result.Items)
+	return &ApiSpecificationList{Pagination: pagination, Items: items}, nil
 }
 
 // Upload the api specification at the specified path for the specified version
@@ -109,8 +93,7 @@ func (a *api_specification) Upload(ctx context.Context, opt ApiSpecificationUplo
 		return nil, err
 	}
 
-	result := ApiSpecificationStub{}
-	return &result, a.client.decodeAndClose(response.Body, &result)
+	return decodeJSON[*ApiSpecificationStub](a.client, response)
 }
 
 // Update an existing api specification using the specified path for the specified version
@@ -127,8 +110,7 @@ func (a *api_specification) Update(ctx context.Context, id string, opt ApiSpecif
 		return nil, err
 	}
 
-	result := ApiSpecificationStub{}
-	return &result, a.client.decodeAndClose(response.Body, &result)
+	return decodeJSON[*ApiSpecificationStub](a.client, response)
 }
 
 // Delete an existing api specification
@@ -9,9 +9,7 @@ import (
 
 func TestApiSpecification_List(t *testing.T) {
 	t.Run("list api specifications", func(t *testing.T) {
-		client, err := NewClient(nil)
-
-		assert.Nil(t, err)
+		client := newCassetteClient(t)
 
 		list, err := client.ApiSpecifications.List(context.Background(), "1.0", ApiSpecificationListOptions{})
 
@@ -22,9 +20,7 @@ func TestApiSpecification_List(t *testing.T) {
 
 func TestApiSpecification_CreateUpdateDelete(t *testing.T) {
 	t.Run("can upload, update, delete", func(t *testing.T) {
-		client, err := NewClient(nil)
-
-		assert.Nil(t, err)
+		client := newCassetteClient(t)
 
 		uploaded, err := client.ApiSpecifications.Upload(context.Background(), ApiSpecificationUploadOptions{
 			SpecPath: "./fixtures/petstore.json",
@@ -0,0 +1,337 @@
+// Package cassette records the HTTP requests of a client to a YAML file and replays them in tests,
+// so that code built on the readme API can be tested without a live project.
+//
+//	recorder, err := cassette.New("fixtures/cassettes/docs.yaml", cassette.ModeFromEnv())
+//	defer recorder.Stop()
+//	client, err := readme.NewClient(&readme.Config{HttpClient: recorder.Client()})
+//
+// Requests are matched on their method, path, query, x-readme-version header and body. JSON
+// bodies are compared by value and multipart bodies by their parts, so field order and multipart
+// boundaries don't matter. The Authorization header and jwtSecret fields are scrubbed before
+// interactions are saved.
+package cassette
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Mode is whether a recorder replays or records interactions
+type Mode int
+
+const (
+	// ModeReplay responds to requests with the interactions of the cassette file
+	ModeReplay Mode = iota
+
+	// ModeRecord sends requests and saves the interactions to the cassette file on Stop
+	ModeRecord
+)
+
+// RecordEnv is the environment variable that makes ModeFromEnv return ModeRecord when set
+const RecordEnv = "CASSETTE_RECORD"
+
+// Scrubbed replaces the values of scrubbed headers and body fields
+const Scrubbed = "[SCRUBBED]"
+
+// ErrNoMatch is returned in replay mode for requests that match no unused interaction
+var ErrNoMatch = errors.New("no cassette interaction matches the request")
+
+// ModeFromEnv returns ModeRecord when the CASSETTE_RECORD environment variable is set, and
+// ModeReplay otherwise
+func ModeFromEnv() Mode {
+	if os.Getenv(RecordEnv) != "" {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// Cassette is the content of a cassette file
+type Cassette struct {
+	Interactions []*Interaction `yaml:"interactions"`
+}
+
+// Interaction is a request and the response it got
+type Interaction struct {
+	Request  Request  `yaml:"request"`
+	Response Response `yaml:"response"`
+}
+
+// Request is a recorded request
+type Request struct {
+	Method  string      `yaml:"method"`
+	URL     string      `yaml:"url"`
+	Headers http.Header `yaml:"headers,omitempty"`
+	Body    string      `yaml:"body,omitempty"`
+}
+
+// Response is a recorded response
+type Response struct {
+	Status  int         `yaml:"status"`
+	Headers http.Header `yaml:"headers,omitempty"`
+	Body    string      `yaml:"body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that records or replays the interactions of a cassette file
+type Recorder struct {
+	// Transport sends the requests in record mode. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// ScrubHeaders are the request and response headers whose values are replaced with Scrubbed
+	ScrubHeaders []string
+
+	// ScrubFields are the JSON object fields, at any depth, whose values are replaced with Scrubbed
+	ScrubFields []string
+
+	path     string
+	mode     Mode
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// New returns a recorder of the cassette file at the path. In replay mode the file is loaded and
+// must exist.
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{
+		ScrubHeaders: []string{"Authorization"},
+		ScrubFields:  []string{"jwtSecret"},
+		path:         path,
+		mode:         mode,
+		cassette:     &Cassette{},
+	}
+
+	if mode == ModeReplay {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read cassette: %w", err)
+		}
+
+		if err := yaml.Unmarshal(data, r.cassette); err != nil {
+			return nil, fmt.Errorf("could not parse cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// Client returns an http.Client using the recorder
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip records or replays a request
+func (r *Recorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	body := []byte{}
+	if request.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("could not read request body: %w", err)
+		}
+		request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	if r.mode == ModeRecord {
+		return r.record(request, body)
+	}
+	return r.replay(request, body)
+}
+
+func (r *Recorder) replay(request *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !matches(&interaction.Request, request, body) {
+			continue
+		}
+		r.used[i] = true
+
+		responseBody := []byte(interaction.Response.Body)
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+			StatusCode:    interaction.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Headers.Clone(),
+			Body:          ioutil.NopCloser(bytes.NewReader(responseBody)),
+			ContentLength: int64(len(responseBody)),
+			Request:       request,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoMatch, request.Method, request.URL.RequestURI())
+}
+
+func (r *Recorder) record(request *http.Request, body []byte) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	response, err := transport.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("could not read response body: %w", err)
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+
+	s := newScrubber(r.ScrubHeaders, r.ScrubFields)
+	interaction := &Interaction{
+		Request: Request{
+			Method:  request.Method,
+			URL:     request.URL.String(),
+			Headers: s.headers(request.Header),
+			Body:    s.body(request.Header.Get("content-type"), body),
+		},
+		Response: Response{
+			Status:  response.StatusCode,
+			Headers: s.headers(response.Header),
+			Body:    s.body(response.Header.Get("content-type"), responseBody),
+		},
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+
+	return response, nil
+}
+
+// Stop saves the recorded interactions to the cassette file in record mode. It does nothing in
+// replay mode.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := yaml.Marshal(r.cassette)
+	if err != nil {
+		return fmt.Errorf("could not marshal cassette: %w", err)
+	}
+
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal(data, node); err != nil {
+		return fmt.Errorf("could not marshal cassette: %w", err)
+	}
+	literalBodies(node)
+
+	if data, err = yaml.Marshal(node); err != nil {
+		return fmt.Errorf("could not marshal cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("could not create cassette directory: %w", err)
+	}
+
+	if err := ioutil.WriteFile(r.path, data, 0644); err != nil {
+		return fmt.Errorf("could not write cassette: %w", err)
+	}
+	return nil
+}
+
+// literalBodies writes bodies as literal blocks, which keep long lines unfolded, unless they have
+// characters literal blocks can't hold, ex. the CRLF line endings of multipart bodies
+func literalBodies(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "body" && value.Kind == yaml.ScalarNode && !strings.ContainsAny(value.Value, "\r\t") {
+				value.Style = yaml.LiteralStyle
+			}
+		}
+	}
+
+	for _, child := range node.Content {
+		literalBodies(child)
+	}
+}
+
+// scrubber replaces sensitive headers and body fields of recorded interactions
+type scrubber struct {
+	headerNames map[string]bool
+	fields      map[string]bool
+}
+
+func newScrubber(headers []string, fields []string) *scrubber {
+	s := &scrubber{headerNames: map[string]bool{}, fields: map[string]bool{}}
+	for _, header := range headers {
+		s.headerNames[http.CanonicalHeaderKey(header)] = true
+	}
+	for _, field := range fields {
+		s.fields[field] = true
+	}
+	return s
+}
+
+func (s *scrubber) headers(header http.Header) http.Header {
+	result := header.Clone()
+	for name := range result {
+		if s.headerNames[http.CanonicalHeaderKey(name)] {
+			result[name] = []string{Scrubbed}
+		}
+	}
+	return result
+}
+
+// body scrubs the fields of JSON bodies. Other bodies, and JSON bodies without scrubbed fields,
+// are returned as is.
+func (s *scrubber) body(contentType string, body []byte) string {
+	if !strings.Contains(contentType, "json") || len(s.fields) == 0 {
+		return string(body)
+	}
+
+	var value interface{}
+	if err := jsonUnmarshal(body, &value); err != nil || !s.scrub(value) {
+		return string(body)
+	}
+
+	data, err := jsonMarshal(value)
+	if err != nil {
+		return string(body)
+	}
+	return string(data)
+}
+
+// scrub replaces the fields of objects at any depth and reports whether any was replaced
+func (s *scrubber) scrub(value interface{}) bool {
+	scrubbed := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, field := range v {
+			if s.fields[name] {
+				v[name] = Scrubbed
+				scrubbed = true
+			} else if s.scrub(field) {
+				scrubbed = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if s.scrub(item) {
+				scrubbed = true
+			}
+		}
+	}
+	return scrubbed
+}
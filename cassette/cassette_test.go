@@ -0,0 +1,81 @@
+package cassette
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("content-type", "application/json")
+		w.Header().Set("x-request-id", "c3a1b9e2")
+		w.Write([]byte(`{"name":"go-readme","jwtSecret":"secret","received":` + string(body) + `}`))
+	}))
+	t.Cleanup(server.Close)
+
+	path := filepath.Join(t.TempDir(), "cassettes", "project.yaml")
+
+	t.Run("records interactions", func(t *testing.T) {
+		recorder, err := New(path, ModeRecord)
+		assert.Nil(t, err)
+
+		request, _ := http.NewRequest("POST", server.URL+"/api/v1/docs?page=1", strings.NewReader(`{"title":"Intro"}`))
+		request.Header.Set("authorization", "Basic a2V5Og==")
+		request.Header.Set("content-type", "application/json")
+		request.Header.Set("x-readme-version", "1.0")
+
+		response, err := recorder.Client().Do(request)
+		assert.Nil(t, err)
+		body, _ := ioutil.ReadAll(response.Body)
+		assert.Contains(t, string(body), `"jwtSecret":"secret"`, "responses are not scrubbed while recording")
+
+		assert.Nil(t, recorder.Stop())
+
+		data, err := ioutil.ReadFile(path)
+		assert.Nil(t, err)
+		assert.NotContains(t, string(data), "a2V5Og==")
+		assert.NotContains(t, string(data), `"secret"`)
+		assert.Contains(t, string(data), Scrubbed)
+	})
+
+	t.Run("replays interactions", func(t *testing.T) {
+		recorder, err := New(path, ModeReplay)
+		assert.Nil(t, err)
+
+		request, _ := http.NewRequest("POST", "https://dash.readme.com/api/v1/docs?page=1", strings.NewReader(`{ "title": "Intro" }`))
+		request.Header.Set("content-type", "application/json")
+		request.Header.Set("x-readme-version", "1.0")
+
+		response, err := recorder.Client().Do(request)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, "c3a1b9e2", response.Header.Get("x-request-id"))
+
+		body, _ := ioutil.ReadAll(response.Body)
+		assert.JSONEq(t, `{"name":"go-readme","jwtSecret":"[SCRUBBED]","received":{"title":"Intro"}}`, string(body))
+
+		_, err = recorder.Client().Do(request)
+		assert.True(t, errors.Is(err, ErrNoMatch), "interactions are replayed once")
+	})
+
+	t.Run("requires the cassette in replay mode", func(t *testing.T) {
+		_, err := New(filepath.Join(t.TempDir(), "missing.yaml"), ModeReplay)
+		assert.Contains(t, err.Error(), "could not read cassette")
+	})
+}
+
+func TestModeFromEnv(t *testing.T) {
+	t.Setenv(RecordEnv, "")
+	assert.Equal(t, ModeReplay, ModeFromEnv())
+
+	t.Setenv(RecordEnv, "1")
+	assert.Equal(t, ModeRecord, ModeFromEnv())
+}
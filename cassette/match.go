@@ -0,0 +1,128 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// versionHeader selects the project version of readme API requests
+const versionHeader = "x-readme-version"
+
+// matches reports whether a recorded request matches a request on its method, path, query,
+// version header and body
+func matches(recorded *Request, request *http.Request, body []byte) bool {
+	if recorded.Method != request.Method {
+		return false
+	}
+
+	u, err := url.Parse(recorded.URL)
+	if err != nil || u.Path != request.URL.Path {
+		return false
+	}
+
+	if !reflect.DeepEqual(normalizeQuery(u.Query()), normalizeQuery(request.URL.Query())) {
+		return false
+	}
+
+	if recorded.Headers.Get(versionHeader) != request.Header.Get(versionHeader) {
+		return false
+	}
+
+	return normalizeBody(recorded.Headers.Get("content-type"), []byte(recorded.Body)) ==
+		normalizeBody(request.Header.Get("content-type"), body)
+}
+
+func normalizeQuery(query url.Values) url.Values {
+	if len(query) == 0 {
+		return nil
+	}
+	return query
+}
+
+// normalizeBody returns a form of the body that doesn't depend on the JSON field order or
+// multipart boundary
+func normalizeBody(contentType string, body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return string(body)
+	}
+
+	switch {
+	case strings.Contains(mediaType, "json"):
+		var value interface{}
+		if err := jsonUnmarshal(body, &value); err != nil {
+			return string(body)
+		}
+		data, err := jsonMarshal(value)
+		if err != nil {
+			return string(body)
+		}
+		return string(data)
+
+	case strings.HasPrefix(mediaType, "multipart/"):
+		parts, err := multipartParts(body, params["boundary"])
+		if err != nil {
+			return string(body)
+		}
+		return parts
+	}
+
+	return string(body)
+}
+
+// multipartParts lists the names, file names and content of the parts of a multipart body,
+// sorted by name
+func multipartParts(body []byte, boundary string) (string, error) {
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+
+	parts := []string{}
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", err
+		}
+
+		content, err := ioutil.ReadAll(part)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, fmt.Sprintf("%s %s\n%s", part.FormName(), part.FileName(), normalizeBody(part.Header.Get("content-type"), content)))
+	}
+
+	sort.Strings(parts)
+	return strings.Join(parts, "\n"), nil
+}
+
+func jsonUnmarshal(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// jsonMarshal marshals without escaping HTML, so that scrubbed bodies stay readable
+func jsonMarshal(v interface{}) ([]byte, error) {
+	buffer := bytes.Buffer{}
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,78 @@
+package cassette
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatches(t *testing.T) {
+	recorded := &Request{
+		Method:  "PUT",
+		URL:     "https://dash.readme.com/api/v1/docs/intro?perPage=2&page=1",
+		Headers: http.Header{"Content-Type": {"application/json"}, "X-Readme-Version": {"1.0"}},
+		Body:    `{"title":"Intro","hidden":false}`,
+	}
+
+	newRequest := func(method string, url string, version string) *http.Request {
+		request, _ := http.NewRequest(method, url, nil)
+		request.Header.Set("content-type", "application/json")
+		if version != "" {
+			request.Header.Set("x-readme-version", version)
+		}
+		return request
+	}
+
+	cases := []struct {
+		name     string
+		request  *http.Request
+		body     string
+		expected bool
+	}{
+		{"same request", newRequest("PUT", "http://127.0.0.1/api/v1/docs/intro?page=1&perPage=2", "1.0"), `{"hidden":false, "title":"Intro"}`, true},
+		{"other method", newRequest("POST", "http://127.0.0.1/api/v1/docs/intro?page=1&perPage=2", "1.0"), `{"title":"Intro","hidden":false}`, false},
+		{"other path", newRequest("PUT", "http://127.0.0.1/api/v1/docs/other?page=1&perPage=2", "1.0"), `{"title":"Intro","hidden":false}`, false},
+		{"other query", newRequest("PUT", "http://127.0.0.1/api/v1/docs/intro?page=2&perPage=2", "1.0"), `{"title":"Intro","hidden":false}`, false},
+		{"other version", newRequest("PUT", "http://127.0.0.1/api/v1/docs/intro?page=1&perPage=2", "2.0"), `{"title":"Intro","hidden":false}`, false},
+		{"other body", newRequest("PUT", "http://127.0.0.1/api/v1/docs/intro?page=1&perPage=2", "1.0"), `{"title":"Intro","hidden":true}`, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, matches(recorded, c.request, []byte(c.body)))
+		})
+	}
+}
+
+func TestNormalizeBody(t *testing.T) {
+	multipartBody := func(content string) (string, string) {
+		body := bytes.Buffer{}
+		writer := multipart.NewWriter(&body)
+		part, _ := writer.CreateFormFile("spec", "petstore.json")
+		part.Write([]byte(content))
+		writer.Close()
+		return writer.FormDataContentType(), body.String()
+	}
+
+	t.Run("ignores multipart boundaries", func(t *testing.T) {
+		firstType, first := multipartBody(`{"openapi":"3.0.0"}`)
+		secondType, second := multipartBody(`{"openapi":"3.0.0"}`)
+		otherType, other := multipartBody(`{"openapi":"3.1.0"}`)
+
+		assert.NotEqual(t, firstType, secondType)
+		assert.Equal(t, normalizeBody(firstType, []byte(first)), normalizeBody(secondType, []byte(second)))
+		assert.NotEqual(t, normalizeBody(firstType, []byte(first)), normalizeBody(otherType, []byte(other)))
+	})
+
+	t.Run("ignores json formatting", func(t *testing.T) {
+		assert.Equal(t, `{"a":1,"b":[true,null]}`, normalizeBody("application/json; charset=utf-8", []byte(`{ "b": [true, null], "a": 1 }`)))
+	})
+
+	t.Run("keeps other bodies", func(t *testing.T) {
+		assert.Equal(t, "a=1&b=2", normalizeBody("application/x-www-form-urlencoded", []byte("a=1&b=2")))
+		assert.Equal(t, "", normalizeBody("application/json", []byte("  ")))
+	})
+}
@@ -18,6 +18,16 @@ type categories struct {
 	client *Client
 }
 
+// CategoryDoc is the summary of a doc within a category, as returned by the ListDocs method
+type CategoryDoc struct {
+	Title    string         `json:"title"`
+	Slug     string         `json:"slug"`
+	Order    int            `json:"order"`
+	Hidden   bool           `json:"hidden"`
+	ID       string         `json:"_id"`
+	Children []*CategoryDoc `json:"children"`
+}
+
 // CategoriesListOptions is a url-encodable data structure for options you can provide to the List endpoint
 type CategoriesListOptions struct {
 	PerPage int `url:"perPage,omitempty"`
@@ -34,31 +44,39 @@ type CategoriesList struct {
 type Categories interface {
 	List(ctx context.Context, options CategoriesListOptions) (*CategoriesList, error)
 	Get(ctx context.Context, slug string) (*Category, error)
+	ListDocs(ctx context.Context, slug string) ([]*CategoryDoc, error)
 }
 
 // List the categories according to some paging options
 func (c *categories) List(ctx context.Context, opt CategoriesListOptions) (*CategoriesList, error) {
-	response, pagination, err := c.client.getPaged(ctx, "categories", opt)
+	items, pagination, err := list[Category](ctx, c.client, "categories", opt, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
-	result := CategoriesList{
-		Pagination: pagination,
-	}
-
-	return &result, c.client.decodeAndClose(response.Body, &result.Items)
+	return &CategoriesList{Pagination: pagination, Items: items}, nil
 }
 
 // Get a category using the specified slug
 func (c *categories) Get(ctx context.Context, slug string) (*Category, error) {
-	response, err := c.client.get(ctx, "categories/"+slug, nil)
+	return getJSON[*Category](ctx, c.client, "categories/"+slug, nil)
+}
 
-	if err != nil {
-		return nil, err
-	}
+// ListDocs lists the docs, and their child docs, within the category specified by the slug
+func (c *categories) ListDocs(ctx context.Context, slug string) ([]*CategoryDoc, error) {
+	return getJSON[[]*CategoryDoc](ctx, c.client, "categories/"+slug+"/docs", nil)
+}
+
+// listAllCategories fetches every page of categories
+func listAllCategories(ctx context.Context, c Categories) ([]*Category, error) {
+	return listAll(func(page int) ([]*Category, *Pagination, error) {
+		list, err := c.List(ctx, CategoriesListOptions{PerPage: 100, Page: page})
+
+		if err != nil {
+			return nil, nil, err
+		}
 
-	result := Category{}
-	return &result, c.client.decodeAndClose(response.Body, &result)
+		return list.Items, list.Pagination, nil
+	})
 }
@@ -10,8 +10,7 @@ import (
 
 func TestCategories_List(t *testing.T) {
 	t.Run("can list all categories", func(t *testing.T) {
-		client, err := NewClient(nil)
-		assert.Nil(t, err)
+		client := newCassetteClient(t)
 
 		opt := CategoriesListOptions{
 			PerPage: 1,
@@ -29,8 +28,7 @@ func TestCategories_List(t *testing.T) {
 	})
 
 	t.Run("can get a category", func(t *testing.T) {
-		client, err := NewClient(nil)
-		assert.Nil(t, err)
+		client := newCassetteClient(t)
 
 		category, err := client.Categories.Get(context.Background(), "documentation")
 
@@ -39,8 +37,7 @@ func TestCategories_List(t *testing.T) {
 	})
 
 	t.Run("returns error when get nonexisting category", func(t *testing.T) {
-		client, err := NewClient(nil)
-		assert.Nil(t, err)
+		client := newCassetteClient(t)
 
 		category, err := client.Categories.Get(context.Background(), "snazzy")
 
@@ -0,0 +1,343 @@
+package readme
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// DefaultChangelogTemplate renders each section of a release as a Markdown heading followed by a bulleted list
+var DefaultChangelogTemplate = template.Must(template.New("changelog").Parse(`{{range .Sections}}### {{.Heading}}
+
+{{range .Items}}* {{.}}
+{{end}}
+{{end}}`))
+
+// DefaultConventionalCommitTypes maps Conventional Commit types to changelog types. Commit types
+// that are not mapped, such as "chore" or "docs", are left out of generated changelogs.
+var DefaultConventionalCommitTypes = map[string]string{
+	"feat":      ChangelogTypeAdded,
+	"fix":       ChangelogTypeFixed,
+	"perf":      ChangelogTypeImproved,
+	"refactor":  ChangelogTypeImproved,
+	"improve":   ChangelogTypeImproved,
+	"deprecate": ChangelogTypeDeprecated,
+	"remove":    ChangelogTypeRemoved,
+	"revert":    ChangelogTypeRemoved,
+}
+
+// changelogTypePrecedence orders changelog types from most to least significant. A release is
+// published with the type of its most significant section.
+var changelogTypePrecedence = []string{
+	ChangelogTypeRemoved,
+	ChangelogTypeDeprecated,
+	ChangelogTypeAdded,
+	ChangelogTypeImproved,
+	ChangelogTypeFixed,
+}
+
+var changelogTypeHeadings = map[string]string{
+	ChangelogTypeAdded:      "Added",
+	ChangelogTypeFixed:      "Fixed",
+	ChangelogTypeImproved:   "Improved",
+	ChangelogTypeDeprecated: "Deprecated",
+	ChangelogTypeRemoved:    "Removed",
+}
+
+// ChangelogRelease is a single release worth of changes, grouped into sections by changelog type
+type ChangelogRelease struct {
+	Title    string
+	Sections []*ChangelogSection
+
+	// Version and Date are the release version and date, when known
+	Version string
+	Date    string
+}
+
+// ChangelogSection is a list of changes of a single changelog type
+type ChangelogSection struct {
+	// Type is the changelog type of the section, ex. ChangelogTypeAdded
+	Type string
+
+	// Heading is the title rendered above the changes
+	Heading string
+
+	// Items are the Markdown descriptions of each change
+	Items []string
+}
+
+// Type returns the most significant changelog type among the release sections
+func (r *ChangelogRelease) Type() string {
+	for _, changelogType := range changelogTypePrecedence {
+		for _, section := range r.Sections {
+			if section.Type == changelogType && len(section.Items) > 0 {
+				return changelogType
+			}
+		}
+	}
+	return ""
+}
+
+// Render the release body using the specified template, or DefaultChangelogTemplate if nil
+func (r *ChangelogRelease) Render(tmpl *template.Template) (string, error) {
+	if tmpl == nil {
+		tmpl = DefaultChangelogTemplate
+	}
+
+	body := new(bytes.Buffer)
+	if err := tmpl.Execute(body, r); err != nil {
+		return "", fmt.Errorf("could not render changelog %q: %w", r.Title, err)
+	}
+
+	return strings.TrimSpace(body.String()) + "\n", nil
+}
+
+// CreateOptions renders the release into the API request body used to create a Changelog
+func (r *ChangelogRelease) CreateOptions(tmpl *template.Template) (*ChangelogCreateOptions, error) {
+	body, err := r.Render(tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChangelogCreateOptions{
+		Title: r.Title,
+		Body:  body,
+		Type:  r.Type(),
+	}, nil
+}
+
+// section returns the section of the specified type, adding it if necessary
+func (r *ChangelogRelease) section(changelogType string, heading string) *ChangelogSection {
+	for _, section := range r.Sections {
+		if section.Heading == heading {
+			return section
+		}
+	}
+
+	section := &ChangelogSection{Type: changelogType, Heading: heading, Items: []string{}}
+	r.Sections = append(r.Sections, section)
+	return section
+}
+
+// sortSections orders the release sections by changelog type precedence, keeping the relative
+// order of sections of the same type
+func (r *ChangelogRelease) sortSections() {
+	rank := func(changelogType string) int {
+		for i, t := range changelogTypePrecedence {
+			if t == changelogType {
+				return i
+			}
+		}
+		return len(changelogTypePrecedence)
+	}
+
+	sort.SliceStable(r.Sections, func(i, j int) bool {
+		return rank(r.Sections[i].Type) < rank(r.Sections[j].Type)
+	})
+}
+
+// ConventionalCommit is a commit message parsed according to https://www.conventionalcommits.org
+type ConventionalCommit struct {
+	Hash        string
+	Type        string
+	Scope       string
+	Description string
+	Body        string
+	Breaking    bool
+}
+
+var conventionalCommitHeader = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?: (.+)$`)
+
+// ParseConventionalCommit parses a commit message, returning false if the message does not
+// follow the Conventional Commits format
+func ParseConventionalCommit(message string) (*ConventionalCommit, bool) {
+	message = strings.TrimSpace(message)
+	header, body := message, ""
+	if i := strings.Index(message, "\n"); i >= 0 {
+		header, body = message[:i], strings.TrimSpace(message[i+1:])
+	}
+
+	match := conventionalCommitHeader.FindStringSubmatch(strings.TrimSpace(header))
+	if match == nil {
+		return nil, false
+	}
+
+	commit := &ConventionalCommit{
+		Type:        strings.ToLower(match[1]),
+		Scope:       match[2],
+		Breaking:    match[3] == "!",
+		Description: match[4],
+		Body:        body,
+	}
+
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "BREAKING CHANGE:") || strings.HasPrefix(line, "BREAKING-CHANGE:") {
+			commit.Breaking = true
+		}
+	}
+
+	return commit, true
+}
+
+// ReadConventionalCommits reads the commits reachable from the "to" ref but not from the "from"
+// ref of the git repository at repoPath, oldest first. Commits that don't follow the Conventional
+// Commits format are skipped. An empty "from" reads the entire history of "to".
+func ReadConventionalCommits(ctx context.Context, repoPath string, from string, to string) ([]*ConventionalCommit, error) {
+	if to == "" {
+		to = "HEAD"
+	}
+
+	revisions := to
+	if from != "" {
+		revisions = from + ".." + to
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "-C", repoPath, "log", "--reverse", "--no-merges", "--format=%H%x1f%B%x1e", revisions, "--")
+	stderr := new(bytes.Buffer)
+	cmd.Stderr = stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not read git history %s: %w: %s", revisions, err, strings.TrimSpace(stderr.String()))
+	}
+
+	result := []*ConventionalCommit{}
+	for _, record := range strings.Split(string(output), "\x1e") {
+		fields := strings.SplitN(strings.TrimSpace(record), "\x1f", 2)
+		if len(fields) != 2 {
+			continue
+		}
+
+		commit, ok := ParseConventionalCommit(fields[1])
+		if !ok {
+			continue
+		}
+
+		commit.Hash = fields[0]
+		result = append(result, commit)
+	}
+
+	return result, nil
+}
+
+// ChangelogGenerator builds a changelog entry from the Conventional Commits between two refs of a
+// local git repository
+type ChangelogGenerator struct {
+	// RepoPath is the path of the git repository. Defaults to the working directory.
+	RepoPath string
+
+	// From is the ref of the previous release. Commits reachable from it are excluded.
+	From string
+
+	// To is the ref of the release. Defaults to HEAD.
+	To string
+
+	// Title of the changelog entry. Defaults to the To ref.
+	Title string
+
+	// Types maps Conventional Commit types to changelog types. Defaults to DefaultConventionalCommitTypes.
+	Types map[string]string
+
+	// Template renders the changelog body from a ChangelogRelease. Defaults to DefaultChangelogTemplate.
+	Template *template.Template
+
+	// Hidden creates the changelog entry hidden, so it can be reviewed before publishing
+	Hidden *bool
+
+	// DryRun prints the changelog entry to Output instead of creating it
+	DryRun bool
+
+	// Output receives the changelog entry when DryRun is set. Defaults to os.Stdout.
+	Output io.Writer
+}
+
+// Release reads the git history and groups the commits into a ChangelogRelease
+func (g *ChangelogGenerator) Release(ctx context.Context) (*ChangelogRelease, error) {
+	repoPath := g.RepoPath
+	if repoPath == "" {
+		repoPath = "."
+	}
+
+	commits, err := ReadConventionalCommits(ctx, repoPath, g.From, g.To)
+	if err != nil {
+		return nil, err
+	}
+
+	types := g.Types
+	if types == nil {
+		types = DefaultConventionalCommitTypes
+	}
+
+	release := &ChangelogRelease{
+		Title:    g.Title,
+		Sections: []*ChangelogSection{},
+	}
+
+	if release.Title == "" {
+		release.Title = g.To
+	}
+
+	for _, commit := range commits {
+		changelogType, ok := types[commit.Type]
+		if !ok {
+			continue
+		}
+
+		item := commit.Description
+		if commit.Scope != "" {
+			item = "**" + commit.Scope + ":** " + item
+		}
+		if commit.Breaking {
+			item = "**BREAKING:** " + item
+		}
+
+		section := release.section(changelogType, changelogTypeHeadings[changelogType])
+		section.Items = append(section.Items, item)
+	}
+
+	release.sortSections()
+
+	return release, nil
+}
+
+// Generate creates the changelog entry for the release, or prints it to Output if DryRun is set.
+// The returned Changelog is nil for dry runs.
+func (g *ChangelogGenerator) Generate(ctx context.Context, changelogs Changelogs) (*Changelog, error) {
+	release, err := g.Release(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if release.Title == "" {
+		return nil, fmt.Errorf("changelog title is required when the To ref is not set")
+	}
+
+	if len(release.Sections) == 0 {
+		return nil, fmt.Errorf("no changelog entries found between %q and %q", g.From, g.To)
+	}
+
+	opt, err := release.CreateOptions(g.Template)
+	if err != nil {
+		return nil, err
+	}
+	opt.Hidden = g.Hidden
+
+	if g.DryRun {
+		output := g.Output
+		if output == nil {
+			output = os.Stdout
+		}
+
+		_, err := fmt.Fprintf(output, "# %s (%s)\n\n%s", opt.Title, opt.Type, opt.Body)
+		return nil, err
+	}
+
+	return changelogs.Create(ctx, *opt)
+}
@@ -0,0 +1,132 @@
+package readme
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestRepo creates a git repository in a temporary directory with an empty commit for each message
+func newTestRepo(t *testing.T, messages ...string) string {
+	dir := t.TempDir()
+
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, output)
+		}
+	}
+
+	git("init", "-q")
+	for _, message := range messages {
+		git("commit", "-q", "--allow-empty", "-m", message)
+	}
+
+	return dir
+}
+
+func TestParseConventionalCommit(t *testing.T) {
+	t.Run("parses type, scope and breaking changes", func(t *testing.T) {
+		commit, ok := ParseConventionalCommit("feat(api)!: add widgets\n\nMore details")
+
+		assert.True(t, ok)
+		assert.Equal(t, "feat", commit.Type)
+		assert.Equal(t, "api", commit.Scope)
+		assert.Equal(t, "add widgets", commit.Description)
+		assert.Equal(t, "More details", commit.Body)
+		assert.True(t, commit.Breaking)
+	})
+
+	t.Run("detects breaking change footers", func(t *testing.T) {
+		commit, ok := ParseConventionalCommit("fix: drop v1 ids\n\nBREAKING CHANGE: ids are now uuids")
+
+		assert.True(t, ok)
+		assert.True(t, commit.Breaking)
+	})
+
+	t.Run("rejects other messages", func(t *testing.T) {
+		_, ok := ParseConventionalCommit("Merge branch 'main'")
+		assert.False(t, ok)
+	})
+}
+
+func TestChangelogGenerator(t *testing.T) {
+	repo := newTestRepo(t,
+		"feat: initial release",
+		"chore: release 1.0",
+		"feat(docs): add search",
+		"fix: handle empty pages",
+		"docs: typo",
+		"deprecate: old search endpoint",
+	)
+
+	t.Run("groups commits between refs", func(t *testing.T) {
+		generator := ChangelogGenerator{
+			RepoPath: repo,
+			From:     "HEAD~4",
+			To:       "HEAD",
+			Title:    "v1.1",
+		}
+
+		release, err := generator.Release(context.Background())
+		assert.Nil(t, err)
+
+		assert.Equal(t, "v1.1", release.Title)
+		assert.Equal(t, ChangelogTypeDeprecated, release.Type())
+		assert.Len(t, release.Sections, 3)
+		assert.Equal(t, "Deprecated", release.Sections[0].Heading)
+		assert.Equal(t, []string{"**docs:** add search"}, release.Sections[1].Items)
+		assert.Equal(t, []string{"handle empty pages"}, release.Sections[2].Items)
+	})
+
+	t.Run("prints dry runs", func(t *testing.T) {
+		output := new(bytes.Buffer)
+		generator := ChangelogGenerator{
+			RepoPath: repo,
+			From:     "HEAD~2",
+			Title:    "v1.1",
+			DryRun:   true,
+			Output:   output,
+		}
+
+		changelog, err := generator.Generate(context.Background(), nil)
+		assert.Nil(t, err)
+		assert.Nil(t, changelog)
+		assert.Equal(t, "# v1.1 (deprecated)\n\n### Deprecated\n\n* old search endpoint\n", output.String())
+	})
+
+	t.Run("creates the changelog", func(t *testing.T) {
+		var created ChangelogCreateOptions
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			json.NewDecoder(r.Body).Decode(&created)
+			writeJSON(w, Changelog{Title: created.Title, Type: created.Type, Body: created.Body})
+		}))
+
+		generator := ChangelogGenerator{
+			RepoPath: repo,
+			From:     "HEAD~4",
+			Title:    "v1.1",
+			Hidden:   newBool(true),
+		}
+
+		changelog, err := generator.Generate(context.Background(), client.Changelogs)
+		assert.Nil(t, err)
+		assert.Equal(t, "v1.1", changelog.Title)
+		assert.Equal(t, ChangelogTypeDeprecated, created.Type)
+		assert.True(t, *created.Hidden)
+		assert.Equal(t, "### Deprecated\n\n* old search endpoint\n\n### Added\n\n* **docs:** add search\n\n### Fixed\n\n* handle empty pages\n", created.Body)
+	})
+
+	t.Run("fails when there is nothing to publish", func(t *testing.T) {
+		generator := ChangelogGenerator{RepoPath: repo, From: "HEAD~1", To: "HEAD~1"}
+
+		_, err := generator.Generate(context.Background(), nil)
+		assert.NotNil(t, err)
+	})
+}
@@ -1,10 +1,8 @@
 package readme
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
+	"net/http"
 )
 
 type changelogs struct {
@@ -86,51 +84,34 @@ func (c *changelogs) Delete(ctx context.Context, slug string) error {
 
 // Update an existing changelog by slug
 func (c *changelogs) Update(ctx context.Context, slug string, changelog ChangelogUpdateOptions) (*Changelog, error) {
-	bodyBytes, err := json.Marshal(changelog)
-
-	if err != nil {
-		return nil, fmt.Errorf("could not marshal request body: %w", err)
-	}
-
-	response, err := c.client.put(ctx, "changelogs/"+slug, bytes.NewBuffer(bodyBytes))
-
-	if err != nil {
-		return nil, err
-	}
-
-	result := Changelog{}
-	return &result, c.client.decodeAndClose(response.Body, &result)
+	return doJSON[ChangelogUpdateOptions, *Changelog](ctx, c.client, http.MethodPut, "changelogs/"+slug, changelog)
 }
 
 // Create a new changelog
 func (c *changelogs) Create(ctx context.Context, changelog ChangelogCreateOptions) (*Changelog, error) {
-	bodyBytes, err := json.Marshal(changelog)
-
-	if err != nil {
-		return nil, fmt.Errorf("could not marshal request body: %w", err)
-	}
-
-	response, err := c.client.post(ctx, "changelogs", bytes.NewBuffer(bodyBytes))
-
-	if err != nil {
-		return nil, err
-	}
-
-	result := Changelog{}
-	return &result, c.client.decodeAndClose(response.Body, &result)
+	return doJSON[ChangelogCreateOptions, *Changelog](ctx, c.client, http.MethodPost, "changelogs", changelog)
 }
 
 // List the changelogs according to some paging options
 func (c *changelogs) List(ctx context.Context, options ChangelogsListOptions) (*ChangelogsList, error) {
-	response, pagination, err := c.client.getPaged(ctx, "changelogs", options)
+	items, pagination, err := list[Changelog](ctx, c.client, "changelogs", options, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
-	result := ChangelogsList{
-		Pagination: pagination,
-	}
+	return &ChangelogsList{Pagination: pagination, Items: items}, nil
+}
+
+// listAllChangelogs fetches every page of changelogs
+func listAllChangelogs(ctx context.Context, c Changelogs) ([]*Changelog, error) {
+	return listAll(func(page int) ([]*Changelog, *Pagination, error) {
+		list, err := c.List(ctx, ChangelogsListOptions{PerPage: 100, Page: page})
+
+		if err != nil {
+			return nil, nil, err
+		}
 
-	return &result, c.client.decodeAndClose(response.Body, &result.Items)
+		return list.Items, list.Pagination, nil
+	})
 }
@@ -10,9 +10,7 @@ import (
 
 func TestChangeLogs_List(t *testing.T) {
 	t.Run("can list changelogs", func(t *testing.T) {
-		client, err := NewClient(nil)
-
-		assert.Nil(t, err)
+		client := newCassetteClient(t)
 
 		list, err := client.Changelogs.List(context.Background(), ChangelogsListOptions{
 			PerPage: 2,
@@ -29,9 +27,7 @@ func TestChangeLogs_List(t *testing.T) {
 
 func TestChangeLogs_CreateUpdateDelete(t *testing.T) {
 	t.Run("can create changelogs", func(t *testing.T) {
-		client, err := NewClient(nil)
-
-		assert.Nil(t, err)
+		client := newCassetteClient(t)
 
 		opt := ChangelogCreateOptions{
 			Title:  "Testing changelogs",
@@ -68,9 +64,7 @@ func TestChangeLogs_CreateUpdateDelete(t *testing.T) {
 	})
 
 	t.Run("can parse error messages", func(t *testing.T) {
-		client, err := NewClient(nil)
-
-		assert.Nil(t, err)
+		client := newCassetteClient(t)
 
 		opt := ChangelogCreateOptions{
 			Body:   "<p>I must be valid markdown</p>",
@@ -78,7 +72,7 @@ func TestChangeLogs_CreateUpdateDelete(t *testing.T) {
 			Hidden: newBool(true),
 		}
 
-		_, err = client.Changelogs.Create(context.Background(), opt)
+		_, err := client.Changelogs.Create(context.Background(), opt)
 		assert.NotNil(t, err)
 		assert.True(t, strings.HasPrefix(err.Error(), "CHANGELOG_INVALID: We couldn't save this changelog (Changelog title cannot be blank). (See "))
 	})
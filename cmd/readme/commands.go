@@ -0,0 +1,915 @@
+package main
+
+import (
+	"flag"
+	"strings"
+
+	readme "github.com/brandonc/go-readme"
+)
+
+// commands are the subcommands by command name. A subcommand named "" runs when no subcommand is
+// given, ex. "readme project".
+var commands map[string]map[string]*command
+
+func init() {
+	commands = map[string]map[string]*command{
+		"docs": {
+			"get":    {usage: "docs get <slug>", summary: "Show a doc", run: docsGet},
+			"create": {usage: "docs create --title <title> --category <id>", summary: "Create a doc", run: docsCreate},
+			"update": {usage: "docs update <slug>", summary: "Update the fields of a doc given as flags", run: docsUpdate},
+			"delete": {usage: "docs delete <slug>", summary: "Delete a doc", run: docsDelete},
+			"search": {usage: "docs search <query>", summary: "Search docs", run: docsSearch},
+		},
+		"categories": {
+			"list": {usage: "categories list", summary: "List categories", run: categoriesList},
+			"get":  {usage: "categories get <slug>", summary: "Show a category", run: categoriesGet},
+			"docs": {usage: "categories docs <slug>", summary: "List the docs of a category", run: categoriesDocs},
+		},
+		"versions": {
+			"list":   {usage: "versions list", summary: "List versions", run: versionsList},
+			"get":    {usage: "versions get <version>", summary: "Show a version", run: versionsGet},
+			"create": {usage: "versions create <version> --from <version>", summary: "Create a version", run: versionsCreate},
+			"update": {usage: "versions update <version>", summary: "Update a version", run: versionsUpdate},
+			"delete": {usage: "versions delete <version>", summary: "Delete a version", run: versionsDelete},
+		},
+		"changelogs": {
+			"list":   {usage: "changelogs list", summary: "List changelogs", run: changelogsList},
+			"create": {usage: "changelogs create --title <title>", summary: "Create a changelog", run: changelogsCreate},
+			"update": {usage: "changelogs update <slug>", summary: "Update a changelog", run: changelogsUpdate},
+			"delete": {usage: "changelogs delete <slug>", summary: "Delete a changelog", run: changelogsDelete},
+		},
+		"custompages": {
+			"list":   {usage: "custompages list", summary: "List custom pages", run: customPagesList},
+			"get":    {usage: "custompages get <slug>", summary: "Show a custom page", run: customPagesGet},
+			"create": {usage: "custompages create --title <title>", summary: "Create a custom page", run: customPagesCreate},
+			"update": {usage: "custompages update <slug>", summary: "Update a custom page", run: customPagesUpdate},
+			"delete": {usage: "custompages delete <slug>", summary: "Delete a custom page", run: customPagesDelete},
+		},
+		"specs": {
+			"list":   {usage: "specs list", summary: "List the API specifications of a version", run: specsList},
+			"upload": {usage: "specs upload <path>", summary: "Upload an API specification to a version", run: specsUpload},
+			"update": {usage: "specs update <id> <path>", summary: "Replace an API specification", run: specsUpdate},
+			"delete": {usage: "specs delete <id>", summary: "Delete an API specification", run: specsDelete},
+		},
+		"project": {
+			"": {usage: "project", summary: "Show the project", run: projectGet},
+		},
+	}
+}
+
+// isSet reports whether a flag was given, to tell an unset flag apart from an explicit zero value
+func isSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// boolIfSet returns a pointer to the value of a flag that was given, or nil
+func boolIfSet(flags *flag.FlagSet, name string, value bool) *bool {
+	if !isSet(flags, name) {
+		return nil
+	}
+	return &value
+}
+
+// pagingFlags registers the --page and --per-page flags
+func pagingFlags(flags *flag.FlagSet) (*int, *int) {
+	return flags.Int("page", 0, "page of results"), flags.Int("per-page", 0, "number of results per page")
+}
+
+// docFlags are the flags shared by docs create and docs update
+type docFlags struct {
+	title, category, slug, docType, body, bodyFile, excerpt, parent, linkURL string
+	hidden                                                                   bool
+	order                                                                    int
+}
+
+func newDocFlags(flags *flag.FlagSet) *docFlags {
+	d := &docFlags{}
+	flags.StringVar(&d.title, "title", "", "title of the doc")
+	flags.StringVar(&d.category, "category", "", "ID of the category of the doc")
+	flags.StringVar(&d.slug, "slug", "", "slug of the doc")
+	flags.StringVar(&d.docType, "type", "", "type of the doc: basic, error or link")
+	flags.StringVar(&d.body, "body", "", "Markdown body of the doc")
+	flags.StringVar(&d.bodyFile, "body-file", "", "file to read the body from, or - for standard input")
+	flags.StringVar(&d.excerpt, "excerpt", "", "excerpt of the doc")
+	flags.StringVar(&d.parent, "parent", "", "ID of the parent doc")
+	flags.StringVar(&d.linkURL, "link-url", "", "URL of a link doc")
+	flags.BoolVar(&d.hidden, "hidden", false, "hide the doc")
+	flags.IntVar(&d.order, "order", 0, "position of the doc in its category")
+	return d
+}
+
+func docTable(doc *readme.Doc) *table {
+	return fieldTable(
+		"title", cell(doc.Title),
+		"slug", doc.Slug,
+		"type", doc.Type,
+		"category", doc.Category,
+		"order", cell(doc.Order),
+		"hidden", cell(doc.Hidden),
+		"excerpt", cell(doc.Excerpt),
+		"updated", doc.UpdatedAt,
+	)
+}
+
+func docsGet(c *cli, args []string) error {
+	args, err := c.parse(c.flagSet("docs get"), args, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	doc, err := client.Docs.Get(c.ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	return c.render(doc, docTable(doc))
+}
+
+func docsCreate(c *cli, args []string) error {
+	flags := c.flagSet("docs create")
+	d := newDocFlags(flags)
+	if _, err := c.parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	body, err := c.readBody(d.body, d.bodyFile)
+	if err != nil {
+		return err
+	}
+
+	opt := readme.DocCreateOptions{
+		Title:     d.title,
+		Category:  d.category,
+		Slug:      d.slug,
+		Type:      d.docType,
+		Body:      body,
+		Excerpt:   d.excerpt,
+		ParentDoc: d.parent,
+		Hidden:    boolIfSet(flags, "hidden", d.hidden),
+	}
+
+	if d.linkURL != "" {
+		link := readme.NewLinkDoc(d.title, d.category, d.linkURL)
+		opt.Type, opt.LinkURL, opt.LinkExternal = link.Type, link.LinkURL, link.LinkExternal
+	}
+
+	if isSet(flags, "order") {
+		opt.Order = &d.order
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	doc, err := client.Docs.Create(c.ctx, opt)
+	if err != nil {
+		return err
+	}
+
+	return c.render(doc, docTable(doc))
+}
+
+func docsUpdate(c *cli, args []string) error {
+	flags := c.flagSet("docs update")
+	d := newDocFlags(flags)
+	args, err := c.parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	body, err := c.readBody(d.body, d.bodyFile)
+	if err != nil {
+		return err
+	}
+
+	// Patch so that only the flags that were given change the doc
+	opt := readme.DocPatchOptions{Hidden: boolIfSet(flags, "hidden", d.hidden)}
+	fields := map[string]**string{
+		"title":    &opt.Title,
+		"category": &opt.Category,
+		"slug":     &opt.Slug,
+		"type":     &opt.Type,
+		"excerpt":  &opt.Excerpt,
+		"parent":   &opt.ParentDoc,
+		"link-url": &opt.LinkURL,
+	}
+	values := map[string]string{
+		"title":    d.title,
+		"category": d.category,
+		"slug":     d.slug,
+		"type":     d.docType,
+		"excerpt":  d.excerpt,
+		"parent":   d.parent,
+		"link-url": d.linkURL,
+	}
+	for name, field := range fields {
+		if isSet(flags, name) {
+			value := values[name]
+			*field = &value
+		}
+	}
+
+	if isSet(flags, "body") || isSet(flags, "body-file") {
+		opt.Body = &body
+	}
+
+	if isSet(flags, "order") {
+		opt.Order = &d.order
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	doc, err := client.Docs.Patch(c.ctx, args[0], opt)
+	if err != nil {
+		return err
+	}
+
+	return c.render(doc, docTable(doc))
+}
+
+func docsDelete(c *cli, args []string) error {
+	args, err := c.parse(c.flagSet("docs delete"), args, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	if err := client.Docs.Delete(c.ctx, args[0]); err != nil {
+		return err
+	}
+
+	return c.render(map[string]string{"deleted": args[0]}, fieldTable("deleted", args[0]))
+}
+
+func docsSearch(c *cli, args []string) error {
+	flags := c.flagSet("docs search")
+	index := flags.String("index", "", "search one kind of page: docs, reference, custompages or changelogs")
+	includeHidden := flags.Bool("include-hidden", false, "include hidden pages")
+	page, perPage := pagingFlags(flags)
+	args, err := c.parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	results, err := client.Docs.Search(c.ctx, args[0], readme.SearchOptions{
+		Index:         *index,
+		IncludeHidden: *includeHidden,
+		Page:          *page,
+		PerPage:       *perPage,
+	})
+	if err != nil {
+		return err
+	}
+
+	t := &table{header: []string{"TITLE", "INDEX", "URL"}}
+	for _, result := range results.Results {
+		t.rows = append(t.rows, []string{cell(result.Title), result.IndexName, result.URL})
+	}
+
+	return c.render(results.Results, t)
+}
+
+func categoriesList(c *cli, args []string) error {
+	flags := c.flagSet("categories list")
+	page, perPage := pagingFlags(flags)
+	if _, err := c.parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	list, err := client.Categories.List(c.ctx, readme.CategoriesListOptions{Page: *page, PerPage: *perPage})
+	if err != nil {
+		return err
+	}
+
+	t := &table{header: []string{"TITLE", "SLUG", "ORDER", "REFERENCE", "ID"}}
+	for _, category := range list.Items {
+		t.rows = append(t.rows, []string{cell(category.Title), category.Slug, cell(category.Order), cell(category.Reference), category.ID})
+	}
+
+	return c.render(list.Items, t)
+}
+
+func categoriesGet(c *cli, args []string) error {
+	args, err := c.parse(c.flagSet("categories get"), args, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	category, err := client.Categories.Get(c.ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	return c.render(category, fieldTable(
+		"title", cell(category.Title),
+		"slug", category.Slug,
+		"order", cell(category.Order),
+		"reference", cell(category.Reference),
+		"version", category.Version,
+		"id", category.ID,
+	))
+}
+
+func categoriesDocs(c *cli, args []string) error {
+	args, err := c.parse(c.flagSet("categories docs"), args, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	docs, err := client.Categories.ListDocs(c.ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	t := &table{header: []string{"TITLE", "SLUG", "ORDER", "HIDDEN"}}
+	var add func(docs []*readme.CategoryDoc, depth int)
+	add = func(docs []*readme.CategoryDoc, depth int) {
+		for _, doc := range docs {
+			t.rows = append(t.rows, []string{strings.Repeat("  ", depth) + cell(doc.Title), doc.Slug, cell(doc.Order), cell(doc.Hidden)})
+			add(doc.Children, depth+1)
+		}
+	}
+	add(docs, 0)
+
+	return c.render(docs, t)
+}
+
+func versionsList(c *cli, args []string) error {
+	if _, err := c.parse(c.flagSet("versions list"), args, 0); err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	list, err := client.Versions.List(c.ctx)
+	if err != nil {
+		return err
+	}
+
+	t := &table{header: []string{"VERSION", "CODENAME", "STABLE", "BETA", "HIDDEN", "DEPRECATED"}}
+	for _, version := range list.Items {
+		t.rows = append(t.rows, []string{version.Version, cell(version.CodeName), cell(version.IsStable), cell(version.IsBeta), cell(version.IsHidden), cell(version.IsDeprecated)})
+	}
+
+	return c.render(list.Items, t)
+}
+
+func versionsGet(c *cli, args []string) error {
+	args, err := c.parse(c.flagSet("versions get"), args, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	version, err := client.Versions.Get(c.ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	return c.render(version, versionTable(version))
+}
+
+func versionTable(version *readme.Version) *table {
+	return fieldTable(
+		"version", version.Version,
+		"codename", cell(version.CodeName),
+		"stable", cell(version.IsStable),
+		"beta", cell(version.IsBeta),
+		"hidden", cell(version.IsHidden),
+		"deprecated", cell(version.IsDeprecated),
+		"forked from", version.ForkedFrom,
+		"created", version.CreatedAt,
+	)
+}
+
+// versionFlags are the flags shared by versions create and versions update
+type versionFlags struct {
+	codename   string
+	from       string
+	stable     bool
+	beta       bool
+	hidden     bool
+	deprecated bool
+}
+
+func newVersionFlags(flags *flag.FlagSet) *versionFlags {
+	v := &versionFlags{}
+	flags.StringVar(&v.codename, "codename", "", "codename of the version")
+	flags.StringVar(&v.from, "from", "", "version to fork the docs from")
+	flags.BoolVar(&v.stable, "stable", false, "make the version the stable version")
+	flags.BoolVar(&v.beta, "beta", false, "mark the version as a beta")
+	flags.BoolVar(&v.hidden, "hidden", false, "hide the version")
+	flags.BoolVar(&v.deprecated, "deprecated", false, "mark the version as deprecated")
+	return v
+}
+
+func versionsCreate(c *cli, args []string) error {
+	flags := c.flagSet("versions create")
+	v := newVersionFlags(flags)
+	args, err := c.parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	version, err := client.Versions.Create(c.ctx, readme.VersionCreateOptions{
+		Version:      args[0],
+		CodeName:     v.codename,
+		From:         v.from,
+		IsStable:     boolIfSet(flags, "stable", v.stable),
+		IsBeta:       boolIfSet(flags, "beta", v.beta),
+		IsHidden:     boolIfSet(flags, "hidden", v.hidden),
+		IsDeprecated: boolIfSet(flags, "deprecated", v.deprecated),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.render(version, versionTable(version))
+}
+
+func versionsUpdate(c *cli, args []string) error {
+	flags := c.flagSet("versions update")
+	v := newVersionFlags(flags)
+	rename := flags.String("rename", "", "new semver of the version")
+	args, err := c.parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	// The API requires the version in the body, so it is kept unless it's renamed
+	opt := readme.VersionUpdateOptions{
+		Version:      args[0],
+		CodeName:     v.codename,
+		From:         v.from,
+		IsStable:     boolIfSet(flags, "stable", v.stable),
+		IsBeta:       boolIfSet(flags, "beta", v.beta),
+		IsHidden:     boolIfSet(flags, "hidden", v.hidden),
+		IsDeprecated: boolIfSet(flags, "deprecated", v.deprecated),
+	}
+	if *rename != "" {
+		opt.Version = *rename
+	}
+
+	version, err := client.Versions.Update(c.ctx, args[0], opt)
+	if err != nil {
+		return err
+	}
+
+	return c.render(version, versionTable(version))
+}
+
+func versionsDelete(c *cli, args []string) error {
+	args, err := c.parse(c.flagSet("versions delete"), args, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	if err := client.Versions.Delete(c.ctx, args[0]); err != nil {
+		return err
+	}
+
+	return c.render(map[string]string{"deleted": args[0]}, fieldTable("deleted", args[0]))
+}
+
+func changelogTable(changelog *readme.Changelog) *table {
+	return fieldTable(
+		"title", cell(changelog.Title),
+		"slug", changelog.Slug,
+		"type", changelog.Type,
+		"hidden", cell(changelog.Hidden),
+		"created", changelog.CreatedAt,
+	)
+}
+
+func changelogsList(c *cli, args []string) error {
+	flags := c.flagSet("changelogs list")
+	page, perPage := pagingFlags(flags)
+	if _, err := c.parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	list, err := client.Changelogs.List(c.ctx, readme.ChangelogsListOptions{Page: *page, PerPage: *perPage})
+	if err != nil {
+		return err
+	}
+
+	t := &table{header: []string{"TITLE", "SLUG", "TYPE", "HIDDEN", "CREATED"}}
+	for _, changelog := range list.Items {
+		t.rows = append(t.rows, []string{cell(changelog.Title), changelog.Slug, changelog.Type, cell(changelog.Hidden), changelog.CreatedAt})
+	}
+
+	return c.render(list.Items, t)
+}
+
+// changelogFlags registers the flags shared by changelogs create and changelogs update
+func changelogFlags(flags *flag.FlagSet) (title, changelogType, body, bodyFile *string, hidden *bool) {
+	title = flags.String("title", "", "title of the changelog")
+	changelogType = flags.String("type", "", "type of the changelog: added, fixed, improved, deprecated or removed")
+	body = flags.String("body", "", "Markdown body of the changelog")
+	bodyFile = flags.String("body-file", "", "file to read the body from, or - for standard input")
+	hidden = flags.Bool("hidden", false, "hide the changelog")
+	return
+}
+
+func changelogsCreate(c *cli, args []string) error {
+	flags := c.flagSet("changelogs create")
+	title, changelogType, body, bodyFile, hidden := changelogFlags(flags)
+	if _, err := c.parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	text, err := c.readBody(*body, *bodyFile)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	changelog, err := client.Changelogs.Create(c.ctx, readme.ChangelogCreateOptions{
+		Title:  *title,
+		Type:   *changelogType,
+		Body:   text,
+		Hidden: boolIfSet(flags, "hidden", *hidden),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.render(changelog, changelogTable(changelog))
+}
+
+func changelogsUpdate(c *cli, args []string) error {
+	flags := c.flagSet("changelogs update")
+	title, changelogType, body, bodyFile, hidden := changelogFlags(flags)
+	args, err := c.parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	text, err := c.readBody(*body, *bodyFile)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	changelog, err := client.Changelogs.Update(c.ctx, args[0], readme.ChangelogUpdateOptions{
+		Title:  *title,
+		Type:   *changelogType,
+		Body:   text,
+		Hidden: boolIfSet(flags, "hidden", *hidden),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.render(changelog, changelogTable(changelog))
+}
+
+func changelogsDelete(c *cli, args []string) error {
+	args, err := c.parse(c.flagSet("changelogs delete"), args, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	if err := client.Changelogs.Delete(c.ctx, args[0]); err != nil {
+		return err
+	}
+
+	return c.render(map[string]string{"deleted": args[0]}, fieldTable("deleted", args[0]))
+}
+
+func customPageTable(page *readme.CustomPage) *table {
+	return fieldTable(
+		"title", cell(page.Title),
+		"slug", page.Slug,
+		"html mode", cell(page.HtmlMode),
+		"hidden", cell(page.Hidden),
+	)
+}
+
+func customPagesList(c *cli, args []string) error {
+	flags := c.flagSet("custompages list")
+	page, perPage := pagingFlags(flags)
+	if _, err := c.parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	list, err := client.CustomPages.List(c.ctx, readme.CustomPagesListOptions{Page: *page, PerPage: *perPage})
+	if err != nil {
+		return err
+	}
+
+	t := &table{header: []string{"TITLE", "SLUG", "HTML MODE", "HIDDEN"}}
+	for _, page := range list.Items {
+		t.rows = append(t.rows, []string{cell(page.Title), page.Slug, cell(page.HtmlMode), cell(page.Hidden)})
+	}
+
+	return c.render(list.Items, t)
+}
+
+func customPagesGet(c *cli, args []string) error {
+	args, err := c.parse(c.flagSet("custompages get"), args, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	page, err := client.CustomPages.Get(c.ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	return c.render(page, customPageTable(page))
+}
+
+// customPageFlags registers the flags shared by custompages create and custompages update
+func customPageFlags(flags *flag.FlagSet) (title, body, bodyFile, html *string, hidden *bool) {
+	title = flags.String("title", "", "title of the custom page")
+	body = flags.String("body", "", "Markdown body of the custom page")
+	bodyFile = flags.String("body-file", "", "file to read the body from, or - for standard input")
+	html = flags.String("html", "", "HTML of the custom page, which enables HTML mode")
+	hidden = flags.Bool("hidden", false, "hide the custom page")
+	return
+}
+
+func customPagesCreate(c *cli, args []string) error {
+	flags := c.flagSet("custompages create")
+	title, body, bodyFile, html, hidden := customPageFlags(flags)
+	if _, err := c.parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	text, err := c.readBody(*body, *bodyFile)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	page, err := client.CustomPages.Create(c.ctx, readme.CustomPageCreateOptions{
+		Title:    *title,
+		Body:     text,
+		Html:     *html,
+		HtmlMode: boolIfSet(flags, "html", *html != ""),
+		Hidden:   boolIfSet(flags, "hidden", *hidden),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.render(page, customPageTable(page))
+}
+
+func customPagesUpdate(c *cli, args []string) error {
+	flags := c.flagSet("custompages update")
+	title, body, bodyFile, html, hidden := customPageFlags(flags)
+	args, err := c.parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	text, err := c.readBody(*body, *bodyFile)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	page, err := client.CustomPages.Update(c.ctx, args[0], readme.CustomPageUpdateOptions{
+		Title:    *title,
+		Body:     text,
+		Html:     *html,
+		HtmlMode: boolIfSet(flags, "html", *html != ""),
+		Hidden:   boolIfSet(flags, "hidden", *hidden),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.render(page, customPageTable(page))
+}
+
+func customPagesDelete(c *cli, args []string) error {
+	args, err := c.parse(c.flagSet("custompages delete"), args, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	if err := client.CustomPages.Delete(c.ctx, args[0]); err != nil {
+		return err
+	}
+
+	return c.render(map[string]string{"deleted": args[0]}, fieldTable("deleted", args[0]))
+}
+
+func specsList(c *cli, args []string) error {
+	flags := c.flagSet("specs list")
+	page, perPage := pagingFlags(flags)
+	if _, err := c.parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	list, err := client.ApiSpecifications.List(c.ctx, c.version, readme.ApiSpecificationListOptions{Page: *page, PerPage: *perPage})
+	if err != nil {
+		return err
+	}
+
+	t := &table{header: []string{"TITLE", "ID", "SOURCE", "LAST SYNCED"}}
+	for _, spec := range list.Items {
+		t.rows = append(t.rows, []string{cell(spec.Title), spec.ID, spec.Source, spec.LastSynced})
+	}
+
+	return c.render(list.Items, t)
+}
+
+func specsUpload(c *cli, args []string) error {
+	args, err := c.parse(c.flagSet("specs upload"), args, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	spec, err := client.ApiSpecifications.Upload(c.ctx, readme.ApiSpecificationUploadOptions{SpecPath: args[0], Version: c.version})
+	if err != nil {
+		return err
+	}
+
+	return c.render(spec, fieldTable("title", cell(spec.Title), "id", spec.ID))
+}
+
+func specsUpdate(c *cli, args []string) error {
+	args, err := c.parse(c.flagSet("specs update"), args, 2)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	spec, err := client.ApiSpecifications.Update(c.ctx, args[0], readme.ApiSpecificationUpdateOptions{SpecPath: args[1]})
+	if err != nil {
+		return err
+	}
+
+	return c.render(spec, fieldTable("title", cell(spec.Title), "id", spec.ID))
+}
+
+func specsDelete(c *cli, args []string) error {
+	args, err := c.parse(c.flagSet("specs delete"), args, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	if err := client.ApiSpecifications.Delete(c.ctx, args[0]); err != nil {
+		return err
+	}
+
+	return c.render(map[string]string{"deleted": args[0]}, fieldTable("deleted", args[0]))
+}
+
+// projectOutput is the project as it is rendered, without its JWT secret
+type projectOutput struct {
+	Name      string `json:"name"`
+	Subdomain string `json:"subdomain"`
+	BaseURL   string `json:"baseUrl"`
+	Plan      string `json:"plan"`
+}
+
+func projectGet(c *cli, args []string) error {
+	if _, err := c.parse(c.flagSet("project"), args, 0); err != nil {
+		return err
+	}
+
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+
+	project, err := client.Project.Get(c.ctx)
+	if err != nil {
+		return err
+	}
+
+	// The JWT secret signs logins to the docs, so it's left out of the output
+	output := projectOutput{Name: project.Name, Subdomain: project.Subdomain, BaseURL: project.BaseURL, Plan: project.Plan}
+	return c.render(output, fieldTable(
+		"name", cell(project.Name),
+		"subdomain", project.Subdomain,
+		"base url", project.BaseURL,
+		"plan", project.Plan,
+	))
+}
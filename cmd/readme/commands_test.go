@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocsCommands(t *testing.T) {
+	t.Run("gets a doc as a table", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v1/docs/intro", r.URL.Path)
+			writeJSON(w, map[string]interface{}{"title": "Introduction", "slug": "intro", "type": "basic", "order": 2})
+		})
+
+		result := runCLI(t, handler, testEnv, "", "docs", "get", "intro")
+
+		assert.Equal(t, 0, result.code)
+		assert.Contains(t, result.stdout, "title     Introduction\n")
+		assert.Contains(t, result.stdout, "order     2\n")
+	})
+
+	t.Run("creates a doc from standard input", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+
+			body := map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			assert.Equal(t, map[string]interface{}{"title": "Setup", "category": "abc", "body": "# Setup", "hidden": false}, body)
+
+			writeJSON(w, map[string]interface{}{"title": "Setup", "slug": "setup"})
+		})
+
+		result := runCLI(t, handler, testEnv, "# Setup\n", "docs", "create", "--title", "Setup", "--category", "abc", "--body-file", "-", "--hidden=false")
+
+		assert.Equal(t, 0, result.code)
+		assert.Contains(t, result.stdout, "slug      setup")
+	})
+
+	t.Run("updates only the given fields", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "PUT", r.Method)
+			assert.Equal(t, "/api/v1/docs/setup", r.URL.Path)
+
+			body := map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			assert.Equal(t, map[string]interface{}{"parentDoc": "", "order": float64(0)}, body)
+
+			writeJSON(w, map[string]interface{}{"slug": "setup"})
+		})
+
+		result := runCLI(t, handler, testEnv, "", "docs", "update", "setup", "--parent", "", "--order", "0", "--output", "json")
+
+		assert.Equal(t, 0, result.code)
+		assert.Contains(t, result.stdout, `"slug": "setup"`)
+	})
+
+	t.Run("deletes a doc", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "DELETE", r.Method)
+			assert.Equal(t, "/api/v1/docs/setup", r.URL.Path)
+		})
+
+		result := runCLI(t, handler, testEnv, "", "--output", "yaml", "docs", "delete", "setup")
+
+		assert.Equal(t, 0, result.code)
+		assert.Equal(t, "deleted: setup\n", result.stdout)
+	})
+
+	t.Run("searches docs", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v1/docs/search", r.URL.Path)
+			assert.Equal(t, "index=reference&page=2&search=pets", r.URL.RawQuery)
+			writeJSON(w, map[string]interface{}{"results": []map[string]string{
+				{"title": "List pets", "indexName": "reference", "url": "/reference/list-pets"},
+			}})
+		})
+
+		result := runCLI(t, handler, testEnv, "", "docs", "search", "pets", "--index", "reference", "--page", "2")
+
+		assert.Equal(t, 0, result.code)
+		assert.Equal(t, "TITLE      INDEX      URL\nList pets  reference  /reference/list-pets\n", result.stdout)
+	})
+}
+
+func TestCategoriesCommands(t *testing.T) {
+	t.Run("lists the docs of a category as a tree", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v1/categories/guides/docs", r.URL.Path)
+			writeJSON(w, []map[string]interface{}{
+				{"title": "Setup", "slug": "setup", "order": 0, "children": []map[string]interface{}{
+					{"title": "Install", "slug": "install", "order": 0},
+				}},
+			})
+		})
+
+		result := runCLI(t, handler, testEnv, "", "categories", "docs", "guides")
+
+		assert.Equal(t, 0, result.code)
+		assert.Equal(t, "TITLE      SLUG     ORDER  HIDDEN\nSetup      setup    0      false\n  Install  install  0      false\n", result.stdout)
+	})
+}
+
+func TestSpecsCommands(t *testing.T) {
+	t.Run("uploads a spec to the version", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "openapi.json")
+		assert.Nil(t, ioutil.WriteFile(path, []byte(`{"openapi":"3.0.0"}`), 0644))
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			assert.Equal(t, "/api/v1/api-specification", r.URL.Path)
+			assert.Equal(t, "1.0", r.Header.Get("x-readme-version"))
+
+			file, header, err := r.FormFile("spec")
+			assert.Nil(t, err)
+			assert.Equal(t, "openapi.json", header.Filename)
+			data, _ := ioutil.ReadAll(file)
+			assert.Equal(t, `{"openapi":"3.0.0"}`, string(data))
+
+			writeJSON(w, map[string]string{"title": "Pets", "_id": "spec1"})
+		})
+
+		result := runCLI(t, handler, testEnv, "", "--version", "1.0", "specs", "upload", path)
+
+		assert.Equal(t, 0, result.code)
+		assert.Contains(t, result.stdout, "id     spec1")
+	})
+
+	t.Run("reports missing files", func(t *testing.T) {
+		result := runCLI(t, http.NotFoundHandler(), testEnv, "", "specs", "upload", filepath.Join(os.TempDir(), "missing-spec.json"))
+
+		assert.Equal(t, 1, result.code)
+		assert.Contains(t, result.stderr, "could not open file specified")
+	})
+}
+
+func TestVersionsCommands(t *testing.T) {
+	t.Run("creates a version", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			assert.Equal(t, "/api/v1/version", r.URL.Path)
+
+			body := map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			assert.Equal(t, map[string]interface{}{"version": "2.0", "codename": "Rex", "from": "1.0", "is_beta": true}, body)
+
+			writeJSON(w, map[string]interface{}{"version": "2.0", "codename": "Rex", "is_beta": true, "forked_from": "1.0"})
+		})
+
+		result := runCLI(t, handler, testEnv, "", "versions", "create", "2.0", "--from", "1.0", "--codename", "Rex", "--beta")
+
+		assert.Equal(t, 0, result.code)
+		assert.Contains(t, result.stdout, "beta         true\n")
+		assert.Contains(t, result.stdout, "forked from  1.0\n")
+	})
+
+	t.Run("updates a version", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "PUT", r.Method)
+			assert.Equal(t, "/api/v1/version/2.0", r.URL.Path)
+
+			body := map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			assert.Equal(t, map[string]interface{}{"version": "2.1", "from": "1.0", "is_stable": true, "is_hidden": false}, body)
+
+			writeJSON(w, map[string]interface{}{"version": "2.1"})
+		})
+
+		result := runCLI(t, handler, testEnv, "", "versions", "update", "2.0", "--rename", "2.1", "--from", "1.0", "--stable", "--hidden=false", "--output", "json")
+
+		assert.Equal(t, 0, result.code)
+		assert.Contains(t, result.stdout, `"version": "2.1"`)
+	})
+
+	t.Run("keeps the version when it isn't renamed", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			assert.Equal(t, "2.0", body["version"])
+
+			writeJSON(w, map[string]interface{}{"version": "2.0"})
+		})
+
+		assert.Equal(t, 0, runCLI(t, handler, testEnv, "", "versions", "update", "2.0", "--deprecated").code)
+	})
+}
+
+func TestProjectCommand(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{"name": "Pets", "subdomain": "pets", "jwtSecret": "shh", "plan": "startup"})
+	})
+
+	for _, output := range []string{"table", "json", "yaml"} {
+		t.Run("leaves out the JWT secret as "+output, func(t *testing.T) {
+			result := runCLI(t, handler, testEnv, "", "project", "--output", output)
+
+			assert.Equal(t, 0, result.code)
+			assert.Contains(t, result.stdout, "pets")
+			assert.NotContains(t, result.stdout, "shh")
+			assert.NotContains(t, result.stdout, "jwtSecret")
+		})
+	}
+}
@@ -0,0 +1,248 @@
+// Command readme manages the content of a ReadMe project from the command line.
+//
+// Usage:
+//
+//	readme [flags] <command> <subcommand> [arguments]
+//
+// Run "readme help" for the list of commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	readme "github.com/brandonc/go-readme"
+)
+
+// errUsage is returned when a command is run with invalid arguments. The usage is printed instead
+// of the error.
+var errUsage = errors.New("invalid usage")
+
+// cli holds the global flags and the streams of a run
+type cli struct {
+	ctx    context.Context
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	profile string
+	apiKey  string
+	version string
+	output  string
+	address string
+}
+
+// command is a subcommand, ex. "docs get"
+type command struct {
+	usage   string
+	summary string
+	run     func(c *cli, args []string) error
+}
+
+func main() {
+	log.SetOutput(ioutil.Discard)
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command line and returns the exit code
+func run(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	c := &cli{ctx: ctx, stdin: stdin, stdout: stdout, stderr: stderr, output: outputTable}
+
+	flags := c.flagSet("readme")
+	flags.Usage = c.usage
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	args = flags.Args()
+	if len(args) == 0 || args[0] == "help" {
+		c.usage()
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	group, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "readme: unknown command %q\n", args[0])
+		c.usage()
+		return 2
+	}
+
+	name := ""
+	if len(args) > 1 {
+		name = args[1]
+	}
+
+	cmd, ok := group[name]
+	if ok && name != "" {
+		args = args[2:]
+	} else if cmd, ok = group[""]; ok {
+		args = args[1:]
+	} else {
+		if name == "" {
+			fmt.Fprintf(stderr, "readme: %s requires a subcommand\n", args[0])
+		} else {
+			fmt.Fprintf(stderr, "readme: unknown command %q\n", args[0]+" "+name)
+		}
+		c.usage()
+		return 2
+	}
+
+	if err := cmd.run(c, args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		if errors.Is(err, errUsage) {
+			fmt.Fprintf(stderr, "usage: readme %s\n", cmd.usage)
+			return 2
+		}
+		fmt.Fprintf(stderr, "readme: %v\n", err)
+
+		var responseErr *readme.ResponseError
+		if errors.As(err, &responseErr) && responseErr.RequestID != "" {
+			fmt.Fprintf(stderr, "request id: %s\n", responseErr.RequestID)
+		}
+		return 1
+	}
+
+	return 0
+}
+
+// flagSet returns a flag set with the global flags. Every command registers them so they can be
+// given before or after the command name.
+func (c *cli) flagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	flags.StringVar(&c.profile, "profile", c.profile, "profile of the config file. Defaults to $README_PROFILE.")
+	flags.StringVar(&c.apiKey, "api-key", c.apiKey, "ReadMe API key. Defaults to $README_API_KEY.")
+	flags.StringVar(&c.version, "version", c.version, "project version (semver, ex. 1.0). Defaults to the stable version.")
+	flags.StringVar(&c.output, "output", c.output, "output format: table, json or yaml")
+	flags.StringVar(&c.address, "address", c.address, "address of the ReadMe API")
+	return flags
+}
+
+// parse parses the flags of a command and checks the number of positional arguments
+func (c *cli) parse(flags *flag.FlagSet, args []string, positional int) ([]string, error) {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		}
+		return nil, errUsage
+	}
+
+	// Flags may follow the positional arguments, ex. "docs get my-doc --output json"
+	rest := []string{}
+	for args := flags.Args(); len(args) > 0; args = flags.Args() {
+		rest = append(rest, args[0])
+		if err := flags.Parse(args[1:]); err != nil {
+			return nil, errUsage
+		}
+	}
+
+	if len(rest) != positional {
+		return nil, errUsage
+	}
+
+	switch c.output {
+	case outputTable, outputJSON, outputYAML:
+	default:
+		return nil, fmt.Errorf("unknown output format %q, expected table, json or yaml", c.output)
+	}
+
+	return rest, nil
+}
+
+// client returns a client for the profile, overridden by the API key, address and version flags
+func (c *cli) client() (*readme.Client, error) {
+	profile, err := readme.LoadProfile(c.profile)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.apiKey != "" {
+		profile.ApiKey, profile.KeyCommand = c.apiKey, ""
+	}
+
+	if c.address != "" {
+		profile.Address = c.address
+	}
+
+	if c.version != "" {
+		profile.Version = c.version
+	}
+
+	if profile.ApiKey == "" && profile.KeyCommand == "" {
+		return nil, errors.New("an API key is required, use --api-key, set README_API_KEY or configure a profile")
+	}
+
+	client, err := profile.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("could not create client: %w", err)
+	}
+
+	return client, nil
+}
+
+func (c *cli) usage() {
+	fmt.Fprint(c.stderr, "usage: readme [flags] <command> [arguments]\n\nCommands:\n")
+
+	names := []string{}
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		subcommands := []string{}
+		for subcommand := range commands[name] {
+			subcommands = append(subcommands, subcommand)
+		}
+		sort.Strings(subcommands)
+
+		for _, subcommand := range subcommands {
+			cmd := commands[name][subcommand]
+			fmt.Fprintf(c.stderr, "  %-48s %s\n", cmd.usage, cmd.summary)
+		}
+	}
+
+	fmt.Fprint(c.stderr, "\nFlags:\n")
+	flags := c.flagSet("readme")
+	flags.VisitAll(func(f *flag.Flag) {
+		fmt.Fprintf(c.stderr, "  --%-12s %s\n", f.Name, f.Usage)
+	})
+}
+
+// readBody returns the text of a --body flag, or the contents of a --body-file flag. A body file of
+// "-" reads standard input.
+func (c *cli) readBody(body string, path string) (string, error) {
+	if path == "" {
+		return body, nil
+	}
+
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = ioutil.ReadAll(c.stdin)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("could not read body: %w", err)
+	}
+
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
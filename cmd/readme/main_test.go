@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// result is the outcome of a run of the command line
+type result struct {
+	code   int
+	stdout string
+	stderr string
+}
+
+// runCLI runs the command line against a local server using the specified handler. Only the
+// specified environment variables are set, and the config file is in a temporary directory.
+func runCLI(t *testing.T, handler http.Handler, env map[string]string, stdin string, args ...string) *result {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	t.Setenv("README_CONFIG_FILE", filepath.Join(t.TempDir(), "config.yaml"))
+	for _, name := range []string{"README_API_KEY", "README_PROFILE", "README_VERSION"} {
+		t.Setenv(name, "")
+	}
+	for name, value := range env {
+		t.Setenv(name, value)
+	}
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	code := run(context.Background(), append([]string{"--address", server.URL}, args...), strings.NewReader(stdin), stdout, stderr)
+	return &result{code: code, stdout: stdout.String(), stderr: stderr.String()}
+}
+
+// writeJSON writes v as a json response body
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+var testEnv = map[string]string{"README_API_KEY": "envKey"}
+
+func TestRun(t *testing.T) {
+	project := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/", r.URL.Path)
+		writeJSON(w, map[string]string{"name": "Pets", "subdomain": "pets", "plan": "startup"})
+	})
+
+	t.Run("prints usage without a command", func(t *testing.T) {
+		result := runCLI(t, project, testEnv, "")
+
+		assert.Equal(t, 2, result.code)
+		assert.Contains(t, result.stderr, "usage: readme")
+		assert.Contains(t, result.stderr, "docs search <query>")
+		assert.Contains(t, result.stderr, "--api-key")
+	})
+
+	t.Run("prints usage for help", func(t *testing.T) {
+		result := runCLI(t, project, testEnv, "", "help")
+
+		assert.Equal(t, 0, result.code)
+		assert.Contains(t, result.stderr, "specs upload <path>")
+	})
+
+	t.Run("rejects unknown commands", func(t *testing.T) {
+		assert.Contains(t, runCLI(t, project, testEnv, "", "pages").stderr, `unknown command "pages"`)
+		assert.Contains(t, runCLI(t, project, testEnv, "", "docs", "publish").stderr, `unknown command "docs publish"`)
+		assert.Contains(t, runCLI(t, project, testEnv, "", "docs").stderr, "docs requires a subcommand")
+	})
+
+	t.Run("rejects missing arguments", func(t *testing.T) {
+		result := runCLI(t, project, testEnv, "", "docs", "get")
+
+		assert.Equal(t, 2, result.code)
+		assert.Equal(t, "usage: readme docs get <slug>\n", result.stderr)
+	})
+
+	t.Run("uses the API key environment variable", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, _, _ := r.BasicAuth()
+			assert.Equal(t, "envKey", user)
+			project(w, r)
+		})
+
+		assert.Equal(t, 0, runCLI(t, handler, testEnv, "", "project").code)
+	})
+
+	t.Run("prefers the API key flag", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, _, _ := r.BasicAuth()
+			assert.Equal(t, "flagKey", user)
+			project(w, r)
+		})
+
+		assert.Equal(t, 0, runCLI(t, handler, testEnv, "", "--api-key", "flagKey", "project").code)
+	})
+
+	t.Run("uses the profile flag", func(t *testing.T) {
+		config := filepath.Join(t.TempDir(), "config.yaml")
+		assert.Nil(t, ioutil.WriteFile(config, []byte("profiles:\n  pets:\n    api_key: profileKey\n    version: \"3.0\"\n"), 0600))
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, _, _ := r.BasicAuth()
+			assert.Equal(t, "profileKey", user)
+			assert.Equal(t, "3.0", r.Header.Get("x-readme-version"))
+			project(w, r)
+		})
+
+		env := map[string]string{"README_CONFIG_FILE": config}
+		assert.Equal(t, 0, runCLI(t, handler, env, "", "--profile", "pets", "project").code)
+
+		result := runCLI(t, handler, env, "", "--profile", "shipping", "project")
+		assert.Equal(t, 1, result.code)
+		assert.Contains(t, result.stderr, `profile "shipping" does not exist`)
+	})
+
+	t.Run("requires an API key", func(t *testing.T) {
+		result := runCLI(t, project, nil, "", "project")
+
+		assert.Equal(t, 1, result.code)
+		assert.Contains(t, result.stderr, "an API key is required")
+	})
+
+	t.Run("sends the version flag before or after the command", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "2.0", r.Header.Get("x-readme-version"))
+			writeJSON(w, map[string]string{"slug": "intro"})
+		})
+
+		assert.Equal(t, 0, runCLI(t, handler, testEnv, "", "--version", "2.0", "docs", "get", "intro").code)
+		assert.Equal(t, 0, runCLI(t, handler, testEnv, "", "docs", "get", "intro", "--version", "2.0").code)
+	})
+
+	t.Run("reports API errors", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("content-type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"DOC_NOTFOUND","message":"The doc couldn't be found.","docs":"https://docs.readme.com/logs/1"}`))
+		})
+
+		result := runCLI(t, handler, testEnv, "", "docs", "get", "missing")
+
+		assert.Equal(t, 1, result.code)
+		assert.Equal(t, "readme: DOC_NOTFOUND: The doc couldn't be found. (See https://docs.readme.com/logs/1)\n", result.stderr)
+	})
+
+	t.Run("reports request ids of API errors", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("x-request-id", "c3a1b9e2")
+			w.WriteHeader(http.StatusBadRequest)
+		})
+
+		result := runCLI(t, handler, testEnv, "", "docs", "get", "intro")
+
+		assert.Equal(t, 1, result.code)
+		assert.Equal(t, "readme: server error (400): \nrequest id: c3a1b9e2\n", result.stderr)
+	})
+
+	t.Run("rejects unknown output formats", func(t *testing.T) {
+		result := runCLI(t, project, testEnv, "", "--output", "xml", "project")
+
+		assert.Equal(t, 1, result.code)
+		assert.Contains(t, result.stderr, `unknown output format "xml"`)
+	})
+}
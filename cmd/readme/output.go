@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// table is the tabular form of a result
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// render writes a result in the output format. JSON and YAML use the API field names.
+func (c *cli) render(v interface{}, t *table) error {
+	switch c.output {
+	case outputJSON:
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("could not encode output: %w", err)
+		}
+		_, err = fmt.Fprintln(c.stdout, string(data))
+		return err
+
+	case outputYAML:
+		// Round trip through JSON so the keys match the API rather than the Go field names
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("could not encode output: %w", err)
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return fmt.Errorf("could not encode output: %w", err)
+		}
+
+		data, err = yaml.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("could not encode output: %w", err)
+		}
+		_, err = c.stdout.Write(data)
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(t.header, "\t"))
+	for _, row := range t.rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// fieldTable is a two column table of field names and values, used for single results
+func fieldTable(fields ...string) *table {
+	t := &table{header: []string{"FIELD", "VALUE"}}
+	for i := 0; i+1 < len(fields); i += 2 {
+		t.rows = append(t.rows, []string{fields[i], fields[i+1]})
+	}
+	return t
+}
+
+// cell formats a value for a table
+func cell(v interface{}) string {
+	switch value := v.(type) {
+	case bool:
+		return strconv.FormatBool(value)
+	case int:
+		return strconv.Itoa(value)
+	case string:
+		value = strings.Join(strings.Fields(value), " ")
+		if runes := []rune(value); len(runes) > 60 {
+			value = string(runes[:57]) + "..."
+		}
+		return value
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCli_Render(t *testing.T) {
+	type item struct {
+		Title  string `json:"title"`
+		Hidden bool   `json:"hidden"`
+	}
+
+	items := []*item{{Title: "Intro"}, {Title: "Setup", Hidden: true}}
+	rows := &table{header: []string{"TITLE", "HIDDEN"}, rows: [][]string{{"Intro", "false"}, {"Setup", "true"}}}
+
+	render := func(output string) string {
+		stdout := &bytes.Buffer{}
+		c := &cli{stdout: stdout, output: output}
+		assert.Nil(t, c.render(items, rows))
+		return stdout.String()
+	}
+
+	t.Run("renders tables", func(t *testing.T) {
+		assert.Equal(t, "TITLE  HIDDEN\nIntro  false\nSetup  true\n", render(outputTable))
+	})
+
+	t.Run("renders json with API field names", func(t *testing.T) {
+		assert.Equal(t, "[\n  {\n    \"title\": \"Intro\",\n    \"hidden\": false\n  },\n  {\n    \"title\": \"Setup\",\n    \"hidden\": true\n  }\n]\n", render(outputJSON))
+	})
+
+	t.Run("renders yaml with API field names", func(t *testing.T) {
+		assert.Equal(t, "- hidden: false\n  title: Intro\n- hidden: true\n  title: Setup\n", render(outputYAML))
+	})
+}
+
+func TestCell(t *testing.T) {
+	assert.Equal(t, "true", cell(true))
+	assert.Equal(t, "3", cell(3))
+	assert.Equal(t, "two lines", cell("two\n  lines"))
+	assert.Equal(t, 60, len(cell(string(bytes.Repeat([]byte("a"), 80)))))
+	assert.Equal(t, strings.Repeat("é", 57)+"...", cell(strings.Repeat("é", 80)))
+	assert.Equal(t, strings.Repeat("é", 60), cell(strings.Repeat("é", 60)))
+}
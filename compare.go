@@ -0,0 +1,264 @@
+package readme
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// VersionComparison is the result of comparing the content of two project versions
+type VersionComparison struct {
+	// A is the first version compared
+	A string
+
+	// B is the second version compared
+	B string
+
+	// DocsOnlyInA are the docs that exist in version A but not in version B
+	DocsOnlyInA []*Doc
+
+	// DocsOnlyInB are the docs that exist in version B but not in version A
+	DocsOnlyInB []*Doc
+
+	// ChangedDocs are the docs that exist in both versions but whose title, body or hidden state differ
+	ChangedDocs []*DocDifference
+
+	// CategoriesOnlyInA are the categories that exist in version A but not in version B
+	CategoriesOnlyInA []*Category
+
+	// CategoriesOnlyInB are the categories that exist in version B but not in version A
+	CategoriesOnlyInB []*Category
+
+	// CategoryOrder are the categories that exist in both versions but are ordered differently
+	// relative to the other categories of both versions
+	CategoryOrder []*CategoryOrderDifference
+}
+
+// DocDifference describes how a doc differs between two versions
+type DocDifference struct {
+	Slug string
+	A    *Doc
+	B    *Doc
+
+	TitleChanged  bool
+	HiddenChanged bool
+	BodyChanged   bool
+
+	// BodyDiff is a unified diff of the doc body from version A to version B
+	BodyDiff string
+}
+
+// CategoryOrderDifference describes a category that is ordered differently between two versions
+type CategoryOrderDifference struct {
+	Slug string
+
+	// OrderA and OrderB are the order values of the category in each version
+	OrderA int
+	OrderB int
+
+	// PositionA and PositionB are the zero-based positions of the category in each sidebar
+	PositionA int
+	PositionB int
+}
+
+// versionContent is all the categories and docs of a single version
+type versionContent struct {
+	categories []*Category
+	docs       map[string]*Doc
+}
+
+// CompareVersions fetches the categories and docs of both versions (semver, ex. "2.9" and "3.0")
+// and reports how their content has drifted apart
+func (c *Client) CompareVersions(ctx context.Context, a, b string) (*VersionComparison, error) {
+	contentA, err := c.fetchVersionContent(ctx, a)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch version %s: %w", a, err)
+	}
+
+	contentB, err := c.fetchVersionContent(ctx, b)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch version %s: %w", b, err)
+	}
+
+	result := &VersionComparison{
+		A:                 a,
+		B:                 b,
+		DocsOnlyInA:       []*Doc{},
+		DocsOnlyInB:       []*Doc{},
+		ChangedDocs:       []*DocDifference{},
+		CategoriesOnlyInA: []*Category{},
+		CategoriesOnlyInB: []*Category{},
+		CategoryOrder:     []*CategoryOrderDifference{},
+	}
+
+	for _, slug := range sortedDocSlugs(contentA.docs) {
+		docA := contentA.docs[slug]
+		docB, ok := contentB.docs[slug]
+		if !ok {
+			result.DocsOnlyInA = append(result.DocsOnlyInA, docA)
+			continue
+		}
+
+		diff, err := compareDocs(a, b, docA, docB)
+		if err != nil {
+			return nil, err
+		}
+
+		if diff != nil {
+			result.ChangedDocs = append(result.ChangedDocs, diff)
+		}
+	}
+
+	for _, slug := range sortedDocSlugs(contentB.docs) {
+		if _, ok := contentA.docs[slug]; !ok {
+			result.DocsOnlyInB = append(result.DocsOnlyInB, contentB.docs[slug])
+		}
+	}
+
+	positionsA := categoryPositions(contentA.categories)
+	positionsB := categoryPositions(contentB.categories)
+
+	// Categories added or removed shift the others, so only the order of the common ones is compared
+	commonA := commonCategories(contentA.categories, positionsB)
+	commonB := commonCategories(contentB.categories, positionsA)
+	relativeB := categoryPositions(commonB)
+
+	for i, category := range commonA {
+		if relativeB[category.Slug] == i {
+			continue
+		}
+
+		other := commonB[relativeB[category.Slug]]
+		result.CategoryOrder = append(result.CategoryOrder, &CategoryOrderDifference{
+			Slug:      category.Slug,
+			OrderA:    category.Order,
+			OrderB:    other.Order,
+			PositionA: positionsA[category.Slug],
+			PositionB: positionsB[category.Slug],
+		})
+	}
+
+	for _, category := range contentA.categories {
+		if _, ok := positionsB[category.Slug]; !ok {
+			result.CategoriesOnlyInA = append(result.CategoriesOnlyInA, category)
+		}
+	}
+
+	for _, category := range contentB.categories {
+		if _, ok := positionsA[category.Slug]; !ok {
+			result.CategoriesOnlyInB = append(result.CategoriesOnlyInB, category)
+		}
+	}
+
+	return result, nil
+}
+
+func (c *Client) fetchVersionContent(ctx context.Context, version string) (*versionContent, error) {
+	return c.WithVersion(version).fetchContent(ctx)
+}
+
+// fetchContent fetches the categories and docs of the version the client is scoped to
+func (c *Client) fetchContent(ctx context.Context) (*versionContent, error) {
+	categories, err := listAllCategories(ctx, c.Categories)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(categories, func(i, j int) bool {
+		return categories[i].Order < categories[j].Order
+	})
+
+	result := &versionContent{
+		categories: categories,
+		docs:       make(map[string]*Doc),
+	}
+
+	for _, category := range categories {
+		docs, err := c.Categories.ListDocs(ctx, category.Slug)
+		if err != nil {
+			return nil, fmt.Errorf("could not list docs of category %s: %w", category.Slug, err)
+		}
+
+		for _, summary := range flattenCategoryDocs(docs) {
+			doc, err := c.Docs.Get(ctx, summary.Slug)
+			if err != nil {
+				return nil, fmt.Errorf("could not get doc %s: %w", summary.Slug, err)
+			}
+			result.docs[summary.Slug] = doc
+		}
+	}
+
+	return result, nil
+}
+
+func compareDocs(versionA, versionB string, a, b *Doc) (*DocDifference, error) {
+	diff := &DocDifference{
+		Slug:          a.Slug,
+		A:             a,
+		B:             b,
+		TitleChanged:  a.Title != b.Title,
+		HiddenChanged: a.Hidden != b.Hidden,
+		BodyChanged:   a.Body != b.Body,
+	}
+
+	if !diff.TitleChanged && !diff.HiddenChanged && !diff.BodyChanged {
+		return nil, nil
+	}
+
+	if diff.BodyChanged {
+		text, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(a.Body),
+			B:        difflib.SplitLines(b.Body),
+			FromFile: versionA + "/" + a.Slug,
+			ToFile:   versionB + "/" + b.Slug,
+			Context:  3,
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("could not diff body of doc %s: %w", a.Slug, err)
+		}
+		diff.BodyDiff = text
+	}
+
+	return diff, nil
+}
+
+// flattenCategoryDocs returns the docs and all of their descendants in sidebar order
+func flattenCategoryDocs(docs []*CategoryDoc) []*CategoryDoc {
+	result := []*CategoryDoc{}
+	for _, doc := range docs {
+		result = append(result, doc)
+		result = append(result, flattenCategoryDocs(doc.Children)...)
+	}
+	return result
+}
+
+func categoryPositions(categories []*Category) map[string]int {
+	result := make(map[string]int, len(categories))
+	for i, category := range categories {
+		result[category.Slug] = i
+	}
+	return result
+}
+
+// commonCategories filters the categories to the ones whose slugs are in the other version
+func commonCategories(categories []*Category, other map[string]int) []*Category {
+	result := make([]*Category, 0, len(categories))
+	for _, category := range categories {
+		if _, ok := other[category.Slug]; ok {
+			result = append(result, category)
+		}
+	}
+	return result
+}
+
+func sortedDocSlugs(docs map[string]*Doc) []string {
+	result := make([]string, 0, len(docs))
+	for slug := range docs {
+		result = append(result, slug)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,148 @@
+package readme
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// versionFixture is the content of a version served by newCompareClient
+type versionFixture struct {
+	categories []*Category
+	docs       map[string][]*CategoryDoc
+	bodies     map[string]*Doc
+}
+
+// newCompareClient serves the categories and docs of each version
+func newCompareClient(t *testing.T, content map[string]versionFixture) *Client {
+	return newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := content[r.Header.Get("x-readme-version")]
+		path := strings.TrimPrefix(r.URL.Path, DefaultBasePath+"/")
+
+		switch {
+		case path == "categories":
+			writePage(w, len(version.categories), version.categories)
+		case strings.HasPrefix(path, "categories/"):
+			slug := strings.TrimSuffix(strings.TrimPrefix(path, "categories/"), "/docs")
+			writeJSON(w, version.docs[slug])
+		case strings.HasPrefix(path, "docs/"):
+			writeJSON(w, version.bodies[strings.TrimPrefix(path, "docs/")])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestClient_CompareVersions(t *testing.T) {
+	t.Run("reports drift between versions", func(t *testing.T) {
+		content := map[string]versionFixture{
+			"2.9": {
+				categories: []*Category{
+					{Slug: "documentation", Order: 0},
+					{Slug: "guides", Order: 1},
+					{Slug: "legacy", Order: 2},
+				},
+				docs: map[string][]*CategoryDoc{
+					"documentation": {{Slug: "getting-started", Children: []*CategoryDoc{{Slug: "auth"}}}},
+					"guides":        {{Slug: "old-guide"}},
+				},
+				bodies: map[string]*Doc{
+					"getting-started": {Slug: "getting-started", Title: "Getting Started", Body: "line one\nline two\n"},
+					"auth":            {Slug: "auth", Title: "Auth", Body: "same"},
+					"old-guide":       {Slug: "old-guide", Title: "Old Guide"},
+				},
+			},
+			"3.0": {
+				categories: []*Category{
+					{Slug: "guides", Order: 0},
+					{Slug: "documentation", Order: 1},
+				},
+				docs: map[string][]*CategoryDoc{
+					"documentation": {{Slug: "getting-started", Children: []*CategoryDoc{{Slug: "auth"}}}},
+					"guides":        {{Slug: "new-guide"}},
+				},
+				bodies: map[string]*Doc{
+					"getting-started": {Slug: "getting-started", Title: "Getting Started", Body: "line one\nline 2\n", Hidden: true},
+					"auth":            {Slug: "auth", Title: "Auth", Body: "same"},
+					"new-guide":       {Slug: "new-guide", Title: "New Guide"},
+				},
+			},
+		}
+
+		client := newCompareClient(t, content)
+
+		result, err := client.CompareVersions(context.Background(), "2.9", "3.0")
+		assert.Nil(t, err)
+
+		assert.Len(t, result.DocsOnlyInA, 1)
+		assert.Equal(t, "old-guide", result.DocsOnlyInA[0].Slug)
+		assert.Len(t, result.DocsOnlyInB, 1)
+		assert.Equal(t, "new-guide", result.DocsOnlyInB[0].Slug)
+
+		assert.Len(t, result.ChangedDocs, 1)
+		changed := result.ChangedDocs[0]
+		assert.Equal(t, "getting-started", changed.Slug)
+		assert.True(t, changed.BodyChanged)
+		assert.True(t, changed.HiddenChanged)
+		assert.False(t, changed.TitleChanged)
+		assert.Contains(t, changed.BodyDiff, "--- 2.9/getting-started")
+		assert.Contains(t, changed.BodyDiff, "-line two")
+		assert.Contains(t, changed.BodyDiff, "+line 2")
+
+		assert.Len(t, result.CategoriesOnlyInA, 1)
+		assert.Equal(t, "legacy", result.CategoriesOnlyInA[0].Slug)
+		assert.Empty(t, result.CategoriesOnlyInB)
+		assert.Len(t, result.CategoryOrder, 2)
+		assert.Equal(t, "documentation", result.CategoryOrder[0].Slug)
+		assert.Equal(t, 0, result.CategoryOrder[0].PositionA)
+		assert.Equal(t, 1, result.CategoryOrder[0].PositionB)
+	})
+
+	t.Run("ignores categories shifted by an inserted category", func(t *testing.T) {
+		client := newCompareClient(t, map[string]versionFixture{
+			"2.9": {categories: []*Category{
+				{Slug: "documentation", Order: 0},
+				{Slug: "guides", Order: 1},
+				{Slug: "faq", Order: 2},
+			}},
+			"3.0": {categories: []*Category{
+				{Slug: "documentation", Order: 0},
+				{Slug: "tutorials", Order: 1},
+				{Slug: "guides", Order: 2},
+				{Slug: "faq", Order: 3},
+			}},
+		})
+
+		result, err := client.CompareVersions(context.Background(), "2.9", "3.0")
+		assert.Nil(t, err)
+
+		assert.Len(t, result.CategoriesOnlyInB, 1)
+		assert.Equal(t, "tutorials", result.CategoriesOnlyInB[0].Slug)
+		assert.Empty(t, result.CategoryOrder)
+	})
+
+	t.Run("reports categories moved past an inserted category", func(t *testing.T) {
+		client := newCompareClient(t, map[string]versionFixture{
+			"2.9": {categories: []*Category{
+				{Slug: "documentation", Order: 0},
+				{Slug: "guides", Order: 1},
+			}},
+			"3.0": {categories: []*Category{
+				{Slug: "guides", Order: 0},
+				{Slug: "tutorials", Order: 1},
+				{Slug: "documentation", Order: 2},
+			}},
+		})
+
+		result, err := client.CompareVersions(context.Background(), "2.9", "3.0")
+		assert.Nil(t, err)
+
+		assert.Equal(t, []*CategoryOrderDifference{
+			{Slug: "documentation", OrderA: 0, OrderB: 2, PositionA: 0, PositionB: 2},
+			{Slug: "guides", OrderA: 1, OrderB: 0, PositionA: 1, PositionB: 0},
+		}, result.CategoryOrder)
+	})
+}
@@ -1,10 +1,8 @@
 package readme
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
+	"net/http"
 )
 
 type custompages struct {
@@ -74,64 +72,39 @@ func (c *custompages) Delete(ctx context.Context, slug string) error {
 
 // Update an existing custompage by slug
 func (c *custompages) Update(ctx context.Context, slug string, custompage CustomPageUpdateOptions) (*CustomPage, error) {
-	bodyBytes, err := json.Marshal(custompage)
-
-	if err != nil {
-		return nil, fmt.Errorf("could not marshal request body: %w", err)
-	}
-
-	response, err := c.client.put(ctx, "custompages/"+slug, bytes.NewBuffer(bodyBytes))
-
-	if err != nil {
-		return nil, err
-	}
-
-	result := CustomPage{}
-	return &result, c.client.decodeAndClose(response.Body, &result)
+	return doJSON[CustomPageUpdateOptions, *CustomPage](ctx, c.client, http.MethodPut, "custompages/"+slug, custompage)
 }
 
 // Create a new changelog
 func (c *custompages) Create(ctx context.Context, custompage CustomPageCreateOptions) (*CustomPage, error) {
-	bodyBytes, err := json.Marshal(custompage)
-
-	if err != nil {
-		return nil, fmt.Errorf("could not marshal request body: %w", err)
-	}
-
-	response, err := c.client.post(ctx, "custompages", bytes.NewBuffer(bodyBytes))
-
-	if err != nil {
-		return nil, err
-	}
-
-	result := CustomPage{}
-	return &result, c.client.decodeAndClose(response.Body, &result)
+	return doJSON[CustomPageCreateOptions, *CustomPage](ctx, c.client, http.MethodPost, "custompages", custompage)
 }
 
 // Get the custompage specified by the slug
 func (c *custompages) Get(ctx context.Context, slug string) (*CustomPage, error) {
-	response, err := c.client.get(ctx, "custompages/"+slug, nil)
-
-	if err != nil {
-		return nil, err
-	}
-
-	result := CustomPage{}
-
-	return &result, c.client.decodeAndClose(response.Body, &result)
+	return getJSON[*CustomPage](ctx, c.client, "custompages/"+slug, nil)
 }
 
 // List the custompages according to some paging options
 func (c *custompages) List(ctx context.Context, options CustomPagesListOptions) (*CustomPagesList, error) {
-	response, pagination, err := c.client.getPaged(ctx, "custompages", options)
+	items, pagination, err := list[CustomPage](ctx, c.client, "custompages", options, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
-	result := CustomPagesList{
-		Pagination: pagination,
-	}
+	return &CustomPagesList{Pagination: pagination, Items: items}, nil
+}
+
+// listAllCustomPages fetches every page of custom pages
+func listAllCustomPages(ctx context.Context, c CustomPages) ([]*CustomPage, error) {
+	return listAll(func(page int) ([]*CustomPage, *Pagination, error) {
+		list, err := c.List(ctx, CustomPagesListOptions{PerPage: 100, Page: page})
+
+		if err != nil {
+			return nil, nil, err
+		}
 
-	return &result, c.client.decodeAndClose(response.Body, &result.Items)
+		return list.Items, list.Pagination, nil
+	})
 }
@@ -10,9 +10,7 @@ import (
 
 func TestCustomPages_List(t *testing.T) {
 	t.Run("can list custompages", func(t *testing.T) {
-		client, err := NewClient(nil)
-
-		assert.Nil(t, err)
+		client := newCassetteClient(t)
 
 		list, err := client.CustomPages.List(context.Background(), CustomPagesListOptions{
 			PerPage: 2,
@@ -30,9 +28,7 @@ func TestCustomPages_List(t *testing.T) {
 
 func TestCustomPages_CreateUpdateDelete(t *testing.T) {
 	t.Run("can create custompages", func(t *testing.T) {
-		client, err := NewClient(nil)
-
-		assert.Nil(t, err)
+		client := newCassetteClient(t)
 
 		opt := CustomPageCreateOptions{
 			Title:    "Testing custompages",
@@ -67,16 +63,14 @@ func TestCustomPages_CreateUpdateDelete(t *testing.T) {
 	})
 
 	t.Run("can parse error messages", func(t *testing.T) {
-		client, err := NewClient(nil)
-
-		assert.Nil(t, err)
+		client := newCassetteClient(t)
 
 		opt := CustomPageCreateOptions{
 			Body:   "# I must be valid markdown",
 			Hidden: newBool(true),
 		}
 
-		_, err = client.CustomPages.Create(context.Background(), opt)
+		_, err := client.CustomPages.Create(context.Background(), opt)
 		assert.NotNil(t, err)
 		assert.True(t, strings.HasPrefix(err.Error(), "CUSTOMPAGE_INVALID: We couldn't save this page (Custom page title cannot be blank). (See "))
 	})
@@ -1,10 +1,10 @@
 package readme
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 )
 
@@ -19,69 +19,293 @@ const (
 )
 
 type Doc struct {
-	Metadata              Metadata `json:"metadata"`
-	Title                 string   `json:"title"`
-	Type                  string   `json:"type"`
-	Slug                  string   `json:"slug"`
-	Excerpt               string   `json:"excerpt"`
-	Body                  string   `json:"body"`
-	Order                 int      `json:"order"`
-	IsReference           bool     `json:"isReference"`
-	Hidden                bool     `json:"hidden"`
-	LinkURL               string   `json:"link_url"`
-	LinkExternal          bool     `json:"link_external"`
-	PendingAlgoliaPublish bool     `json:"pendingAlgoliaPublish"`
-	PreviousSlug          string   `json:"previousSlug"`
-	SlugUpdatedAt         string   `json:"slugUpdatedAt"`
-	User                  string   `json:"user"`
-	Project               string   `json:"project"`
-	Category              string   `json:"category"`
-	CreatedAt             string   `json:"createdAt"`
-	UpdatedAt             string   `json:"updatedAt"`
-	Version               string   `json:"version"`
-	IsAPI                 bool     `json:"isApi"`
-	ID                    string   `json:"id"`
-	BodyHTML              string   `json:"body_html"`
+	Metadata              Metadata  `json:"metadata"`
+	Title                 string    `json:"title"`
+	Type                  string    `json:"type"`
+	Slug                  string    `json:"slug"`
+	Excerpt               string    `json:"excerpt"`
+	Body                  string    `json:"body"`
+	Order                 int       `json:"order"`
+	IsReference           bool      `json:"isReference"`
+	Hidden                bool      `json:"hidden"`
+	LinkURL               string    `json:"link_url"`
+	LinkExternal          bool      `json:"link_external"`
+	PendingAlgoliaPublish bool      `json:"pendingAlgoliaPublish"`
+	PreviousSlug          string    `json:"previousSlug"`
+	SlugUpdatedAt         string    `json:"slugUpdatedAt"`
+	User                  string    `json:"user"`
+	Project               string    `json:"project"`
+	Category              string    `json:"category"`
+	CreatedAt             string    `json:"createdAt"`
+	UpdatedAt             string    `json:"updatedAt"`
+	Version               string    `json:"version"`
+	IsAPI                 bool      `json:"isApi"`
+	ID                    string    `json:"id"`
+	BodyHTML              string    `json:"body_html"`
+	Error                 *DocError `json:"error,omitempty"`
 }
 
 type DocError struct {
 	Code string `json:"code"`
 }
 
+// DocCreateOptions is the API request body when creating a doc. Use Validate, or the NewLinkDoc and
+// NewErrorDoc helpers, to check the options before sending them.
 type DocCreateOptions struct {
-	Title     string    `json:"title"`
-	Category  string    `json:"category"`
-	Type      string    `json:"type,omitempty"`
-	Hidden    *bool     `json:"hidden,omitempty"`
-	Order     *int      `json:"order,omitempty"`
-	ParentDoc string    `json:"parentDoc,omitempty"`
-	Error     *DocError `json:"error,omitempty"`
+	Title        string    `json:"title"`
+	Category     string    `json:"category"`
+	Slug         string    `json:"slug,omitempty"`
+	Type         string    `json:"type,omitempty"`
+	Body         string    `json:"body,omitempty"`
+	Excerpt      string    `json:"excerpt,omitempty"`
+	Metadata     *Metadata `json:"metadata,omitempty"`
+	Hidden       *bool     `json:"hidden,omitempty"`
+	Order        *int      `json:"order,omitempty"`
+	ParentDoc    string    `json:"parentDoc,omitempty"`
+	LinkURL      string    `json:"link_url,omitempty"`
+	LinkExternal bool      `json:"link_external,omitempty"`
+	Error        *DocError `json:"error,omitempty"`
 }
 
+// NewLinkDoc returns the options to create a doc that links to another page. Links to other sites
+// are opened in a new tab.
+func NewLinkDoc(title string, category string, linkURL string) DocCreateOptions {
+	u, err := url.Parse(linkURL)
+
+	return DocCreateOptions{
+		Title:        title,
+		Category:     category,
+		Type:         DocTypeLink,
+		LinkURL:      linkURL,
+		LinkExternal: err == nil && u.IsAbs(),
+	}
+}
+
+// NewErrorDoc returns the options to create a doc that documents the error with the given code
+func NewErrorDoc(title string, category string, code string) DocCreateOptions {
+	return DocCreateOptions{
+		Title:    title,
+		Category: category,
+		Type:     DocTypeError,
+		Error:    &DocError{Code: code},
+	}
+}
+
+// Validate checks that the options describe a doc the API accepts
+func (o DocCreateOptions) Validate() error {
+	if o.Title == "" {
+		return fmt.Errorf("doc title is required")
+	}
+
+	if o.Category == "" {
+		return fmt.Errorf("doc category is required")
+	}
+
+	switch o.Type {
+	case "", DocTypeBasic:
+	case DocTypeLink:
+		if o.LinkURL == "" {
+			return fmt.Errorf("link docs require a link URL")
+		}
+		if _, err := url.Parse(o.LinkURL); err != nil {
+			return fmt.Errorf("could not parse link URL: %w", err)
+		}
+	case DocTypeError:
+		if o.Error == nil || o.Error.Code == "" {
+			return fmt.Errorf("error docs require an error code")
+		}
+	default:
+		return fmt.Errorf("unknown doc type %q", o.Type)
+	}
+
+	if o.LinkURL != "" && o.Type != DocTypeLink {
+		return fmt.Errorf("only link docs can have a link URL")
+	}
+
+	if o.Error != nil && o.Type != DocTypeError {
+		return fmt.Errorf("only error docs can have an error code")
+	}
+
+	return nil
+}
+
+// DocUpdateOptions is the API request body when updating a doc. Empty fields are left unchanged;
+// use DocPatchOptions to set a field to its zero value.
 type DocUpdateOptions struct {
-	Title     string    `json:"title"`
-	Category  string    `json:"category"`
-	Type      string    `json:"type,omitempty"`
-	Hidden    *bool     `json:"hidden,omitempty"`
-	Order     *int      `json:"order,omitempty"`
-	ParentDoc string    `json:"parentDoc,omitempty"`
-	Error     *DocError `json:"error,omitempty"`
+	Title        string                 `json:"title,omitempty"`
+	Category     string                 `json:"category,omitempty"`
+	Slug         string                 `json:"slug,omitempty"`
+	Body         string                 `json:"body,omitempty"`
+	Excerpt      string                 `json:"excerpt,omitempty"`
+	Metadata     *MetadataUpdateOptions `json:"metadata,omitempty"`
+	Type         string                 `json:"type,omitempty"`
+	Hidden       *bool                  `json:"hidden,omitempty"`
+	Order        *int                   `json:"order,omitempty"`
+	ParentDoc    string                 `json:"parentDoc,omitempty"`
+	LinkURL      string                 `json:"link_url,omitempty"`
+	LinkExternal *bool                  `json:"link_external,omitempty"`
+	Error        *DocError              `json:"error,omitempty"`
+}
+
+// DocPatchOptions is the API request body when patching a doc. Only the fields that are set are
+// sent, so a pointer to an empty string clears a field, ex. ParentDoc to move a doc to the top level.
+type DocPatchOptions struct {
+	Title        *string               `json:"title,omitempty"`
+	Category     *string               `json:"category,omitempty"`
+	Slug         *string               `json:"slug,omitempty"`
+	Body         *string               `json:"body,omitempty"`
+	Excerpt      *string               `json:"excerpt,omitempty"`
+	Metadata     *MetadataPatchOptions `json:"metadata,omitempty"`
+	Type         *string               `json:"type,omitempty"`
+	Hidden       *bool                 `json:"hidden,omitempty"`
+	Order        *int                  `json:"order,omitempty"`
+	ParentDoc    *string               `json:"parentDoc,omitempty"`
+	LinkURL      *string               `json:"link_url,omitempty"`
+	LinkExternal *bool                 `json:"link_external,omitempty"`
+	Error        *DocError             `json:"error,omitempty"`
+}
+
+// MetadataUpdateOptions is the metadata of DocUpdateOptions. Empty fields are left unchanged.
+type MetadataUpdateOptions struct {
+	Image       []string `json:"image,omitempty"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// MetadataPatchOptions is the metadata of DocPatchOptions. Only the fields that are set are sent.
+type MetadataPatchOptions struct {
+	Image       *[]string `json:"image,omitempty"`
+	Title       *string   `json:"title,omitempty"`
+	Description *string   `json:"description,omitempty"`
+}
+
+const (
+	// SearchIndexDocs searches guides
+	SearchIndexDocs = "docs"
+
+	// SearchIndexReference searches API reference pages
+	SearchIndexReference = "reference"
+
+	// SearchIndexCustomPages searches custom pages
+	SearchIndexCustomPages = "custompages"
+
+	// SearchIndexChangelogs searches changelogs
+	SearchIndexChangelogs = "changelogs"
+)
+
+// SearchOptions are the options available when searching docs
+type SearchOptions struct {
+	// Version is the project version (semver, ex. "1.0") to search. Defaults to the client's version.
+	Version string `url:"-"`
+
+	// Index restricts the search to one kind of page, ex. SearchIndexReference
+	Index string `url:"index,omitempty"`
+
+	// IncludeHidden includes hidden pages in the results
+	IncludeHidden bool `url:"includeHidden,omitempty"`
+
+	PerPage int `url:"perPage,omitempty"`
+	Page    int `url:"page,omitempty"`
+
+	// Fallback is searched instead when the API is unavailable
+	Fallback *ProjectExport `url:"-"`
+}
+
+// docSearchQuery is the query string of the search endpoint
+type docSearchQuery struct {
+	Search string `url:"search"`
+	SearchOptions
 }
 
 type DocSearchResults struct {
-	Results []*DocSearchResult `json:"results"`
+	Pagination *Pagination        `json:"-"`
+	Results    []*DocSearchResult `json:"results"`
+
+	// Offline reports whether the results came from SearchOptions.Fallback
+	Offline bool `json:"-"`
 }
 
 type DocSearchResult struct {
-	IndexName    string `json:"indexName"`
-	Title        string `json:"title"`
-	Slug         string `json:"slug"`
-	Project      string `json:"project"`
-	ReferenceID  string `json:"referenceId"`
-	Subdomain    string `json:"subdomain"`
-	InternalLink string `json:"internalLink"`
-	ObjectID     string `json:"objectID"`
-	URL          string `json:"url"`
+	IndexName    string               `json:"indexName"`
+	Title        string               `json:"title"`
+	Slug         string               `json:"slug"`
+	Excerpt      string               `json:"excerpt"`
+	Hidden       bool                 `json:"hidden"`
+	Project      string               `json:"project"`
+	ReferenceID  string               `json:"referenceId"`
+	Subdomain    string               `json:"subdomain"`
+	InternalLink string               `json:"internalLink"`
+	ObjectID     string               `json:"objectID"`
+	URL          string               `json:"url"`
+	Highlight    *DocSearchHighlights `json:"_highlightResult,omitempty"`
+}
+
+// DocSearchHighlights are the matches of a search result, with the matched words wrapped in <em> tags
+type DocSearchHighlights struct {
+	Title   *DocSearchHighlight `json:"title,omitempty"`
+	Excerpt *DocSearchHighlight `json:"excerpt,omitempty"`
+	Body    *DocSearchHighlight `json:"body,omitempty"`
+}
+
+// DocSearchHighlight is the highlighted value of a single field of a search result
+type DocSearchHighlight struct {
+	Value        string   `json:"value"`
+	MatchLevel   string   `json:"matchLevel"`
+	MatchedWords []string `json:"matchedWords"`
+}
+
+// DocSearchIterator iterates over the results of a search across pages
+type DocSearchIterator struct {
+	docs    Docs
+	search  string
+	opt     SearchOptions
+	results []*DocSearchResult
+	current *DocSearchResult
+	done    bool
+	err     error
+}
+
+// NewDocSearchIterator returns an iterator over every result of the search, starting at the page
+// of the options
+func NewDocSearchIterator(docs Docs, search string, opt SearchOptions) *DocSearchIterator {
+	if opt.Page == 0 {
+		opt.Page = 1
+	}
+	return &DocSearchIterator{docs: docs, search: search, opt: opt}
+}
+
+// Next advances to the next result, fetching the next page when needed. It returns false when
+// there are no more results or an error occurred.
+func (it *DocSearchIterator) Next(ctx context.Context) bool {
+	for len(it.results) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+
+		page, err := it.docs.Search(ctx, it.search, it.opt)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.results = page.Results
+		// Hidden results are filtered out of the page, so an empty page doesn't mean the end
+		it.done = page.Pagination == nil || page.Pagination.Next == ""
+		it.opt.Page++
+	}
+
+	it.current, it.results = it.results[0], it.results[1:]
+	return true
+}
+
+// Result returns the current result
+func (it *DocSearchIterator) Result() *DocSearchResult {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *DocSearchIterator) Err() error {
+	return it.err
 }
 
 // Changelogs describes the API methods available for the Changelogs API https://docs.readme.com/reference/getcustompages
@@ -89,67 +313,102 @@ type Docs interface {
 	Get(ctx context.Context, slug string) (*Doc, error)
 	Create(ctx context.Context, doc DocCreateOptions) (*Doc, error)
 	Update(ctx context.Context, slug string, doc DocUpdateOptions) (*Doc, error)
+	Patch(ctx context.Context, slug string, doc DocPatchOptions) (*Doc, error)
 	Delete(ctx context.Context, slug string) error
-	Search(ctx context.Context, search string) (*DocSearchResults, error)
+	Search(ctx context.Context, search string, opt SearchOptions) (*DocSearchResults, error)
 }
 
 func (d *docs) Get(ctx context.Context, slug string) (*Doc, error) {
-	response, err := d.client.get(ctx, "docs/"+slug, nil)
+	return getJSON[*Doc](ctx, d.client, "docs/"+slug, nil)
+}
 
-	if err != nil {
-		return nil, err
+func (d *docs) Create(ctx context.Context, doc DocCreateOptions) (*Doc, error) {
+	if err := doc.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid doc: %w", err)
 	}
 
-	result := Doc{}
-	return &result, d.client.decodeAndClose(response.Body, &result)
+	return doJSON[DocCreateOptions, *Doc](ctx, d.client, http.MethodPost, "docs", doc)
 }
 
-func (d *docs) Create(ctx context.Context, doc DocCreateOptions) (*Doc, error) {
-	bodyBytes, err := json.Marshal(doc)
+// Update an existing doc by slug
+func (d *docs) Update(ctx context.Context, slug string, doc DocUpdateOptions) (*Doc, error) {
+	return doJSON[DocUpdateOptions, *Doc](ctx, d.client, http.MethodPut, "docs/"+slug, doc)
+}
 
-	if err != nil {
-		return nil, fmt.Errorf("could not marshal request body: %w", err)
-	}
+// Patch changes only the fields of the doc that are set in the options
+func (d *docs) Patch(ctx context.Context, slug string, doc DocPatchOptions) (*Doc, error) {
+	return doJSON[DocPatchOptions, *Doc](ctx, d.client, http.MethodPut, "docs/"+slug, doc)
+}
 
-	response, err := d.client.post(ctx, "docs", bytes.NewBuffer(bodyBytes))
+func (d *docs) Delete(ctx context.Context, slug string) error {
+	_, err := d.client.delete(ctx, "docs/"+slug)
+	return err
+}
 
-	if err != nil {
-		return nil, err
+// Search the docs of a version. When the API is unavailable and the options have a fallback
+// export, the export is searched instead.
+func (d *docs) Search(ctx context.Context, search string, opt SearchOptions) (*DocSearchResults, error) {
+	client := d.client
+	if opt.Version != "" {
+		client = client.WithVersion(opt.Version)
 	}
 
-	result := Doc{}
-	return &result, d.client.decodeAndClose(response.Body, &result)
-}
+	path, err := addOptions("docs/search", docSearchQuery{Search: search, SearchOptions: opt})
+	if err != nil {
+		return nil, fmt.Errorf("could not encode url options: %w", err)
+	}
 
-func (d *docs) Update(ctx context.Context, slug string, doc DocUpdateOptions) (*Doc, error) {
-	bodyBytes, err := json.Marshal(doc)
+	response, err := client.post(ctx, path, nil)
 
 	if err != nil {
-		return nil, fmt.Errorf("could not marshal request body: %w", err)
+		if opt.Fallback != nil && unavailable(ctx, err) {
+			return opt.Fallback.Search(search, opt), nil
+		}
+		return nil, err
 	}
 
-	response, err := d.client.post(ctx, "docs/"+slug, bytes.NewBuffer(bodyBytes))
+	result := DocSearchResults{}
+	if response.Header.Get("x-total-count") != "" {
+		if result.Pagination, err = client.parsePaginationResponse(&response.Header); err != nil {
+			response.Body.Close()
+			return nil, err
+		}
+	}
 
-	if err != nil {
+	if err := client.decodeAndClose(response.Body, &result); err != nil {
 		return nil, err
 	}
 
-	result := Doc{}
-	return &result, d.client.decodeAndClose(response.Body, &result)
-}
+	if !opt.IncludeHidden {
+		visible := make([]*DocSearchResult, 0, len(result.Results))
+		for _, doc := range result.Results {
+			if !doc.Hidden {
+				visible = append(visible, doc)
+			}
+		}
+		result.Results = visible
+	}
 
-func (d *docs) Delete(ctx context.Context, slug string) error {
-	_, err := d.client.delete(ctx, "docs/"+slug)
-	return err
+	return &result, nil
 }
 
-func (d *docs) Search(ctx context.Context, search string) (*DocSearchResults, error) {
-	response, err := d.client.post(ctx, "docs/search?search="+url.QueryEscape(search), nil)
+// unavailable reports whether the error means the API could not be reached or is failing. Errors
+// after the context is cancelled or its deadline passes don't count, since the caller has given
+// up on the search; a timeout of the HTTP client does.
+func unavailable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
 
-	if err != nil {
-		return nil, err
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true
 	}
 
-	result := DocSearchResults{}
-	return &result, d.client.decodeAndClose(response.Body, &result)
+	var responseErr *ResponseError
+	if errors.As(err, &responseErr) {
+		return responseErr.StatusCode >= 500 || responseErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	return false
 }
@@ -2,27 +2,29 @@ package readme
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestDocs_Search(t *testing.T) {
 	t.Run("can search by query", func(t *testing.T) {
-		client, err := NewClient(nil)
+		client := newCassetteClient(t)
 
-		assert.Nil(t, err)
-
-		results, err := client.Docs.Search(context.Background(), "readme")
+		results, err := client.Docs.Search(context.Background(), "readme", SearchOptions{})
 
 		assert.Nil(t, err)
 		assert.Greater(t, len(results.Results), 0)
 	})
 
 	t.Run("get doc by slug", func(t *testing.T) {
-		client, err := NewClient(nil)
-
-		assert.Nil(t, err)
+		client := newCassetteClient(t)
 
 		result, err := client.Docs.Get(context.Background(), "getting-started")
 
@@ -37,3 +39,225 @@ func TestDocs_Search(t *testing.T) {
 		assert.Equal(t, "basic", result.Type)
 	})
 }
+
+func TestDocs_Patch(t *testing.T) {
+	t.Run("sends only the fields that are set", func(t *testing.T) {
+		var method, body string
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := ioutil.ReadAll(r.Body)
+			method, body = r.Method, string(data)
+			writeJSON(w, Doc{Slug: "getting-started"})
+		}))
+
+		parent, order := "", 2
+		_, err := client.Docs.Patch(context.Background(), "getting-started", DocPatchOptions{
+			ParentDoc: &parent,
+			Order:     &order,
+			Hidden:    newBool(false),
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.MethodPut, method)
+		assert.JSONEq(t, `{"parentDoc": "", "order": 2, "hidden": false}`, body)
+	})
+
+	t.Run("updates with put and omits empty fields", func(t *testing.T) {
+		var method, body string
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := ioutil.ReadAll(r.Body)
+			method, body = r.Method, string(data)
+			writeJSON(w, Doc{Slug: "getting-started"})
+		}))
+
+		_, err := client.Docs.Update(context.Background(), "getting-started", DocUpdateOptions{
+			Body:     "# Hello",
+			Metadata: &MetadataUpdateOptions{Title: "Hello"},
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.MethodPut, method)
+		assert.JSONEq(t, `{"body": "# Hello", "metadata": {"title": "Hello"}}`, body)
+	})
+
+	t.Run("patches only the metadata fields that are set", func(t *testing.T) {
+		var body string
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := ioutil.ReadAll(r.Body)
+			body = string(data)
+			writeJSON(w, Doc{Slug: "getting-started"})
+		}))
+
+		title, description := "Hello", ""
+		_, err := client.Docs.Patch(context.Background(), "getting-started", DocPatchOptions{
+			Metadata: &MetadataPatchOptions{Title: &title},
+		})
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"metadata": {"title": "Hello"}}`, body)
+
+		_, err = client.Docs.Patch(context.Background(), "getting-started", DocPatchOptions{
+			Metadata: &MetadataPatchOptions{Description: &description, Image: &[]string{}},
+		})
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"metadata": {"description": "", "image": []}}`, body)
+	})
+}
+
+func TestDocCreateOptions_Validate(t *testing.T) {
+	t.Run("accepts link and error docs", func(t *testing.T) {
+		link := NewLinkDoc("Status", "abc123", "https://status.example.com")
+		assert.Nil(t, link.Validate())
+		assert.True(t, link.LinkExternal)
+		assert.False(t, NewLinkDoc("Guide", "abc123", "/docs/guide").LinkExternal)
+
+		errorDoc := NewErrorDoc("Not Found", "abc123", "404")
+		assert.Nil(t, errorDoc.Validate())
+		assert.Equal(t, "404", errorDoc.Error.Code)
+	})
+
+	t.Run("rejects invalid docs", func(t *testing.T) {
+		assert.EqualError(t, DocCreateOptions{Category: "abc123"}.Validate(), "doc title is required")
+		assert.EqualError(t, NewLinkDoc("Status", "abc123", "").Validate(), "link docs require a link URL")
+		assert.EqualError(t, NewErrorDoc("Oops", "abc123", "").Validate(), "error docs require an error code")
+		assert.EqualError(t, DocCreateOptions{Title: "A", Category: "abc123", LinkURL: "/x"}.Validate(), "only link docs can have a link URL")
+		assert.EqualError(t, DocCreateOptions{Title: "A", Category: "abc123", Type: "video"}.Validate(), `unknown doc type "video"`)
+	})
+
+	t.Run("validates before sending", func(t *testing.T) {
+		requests := 0
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			data, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t, `{"title": "Status", "category": "abc123", "type": "link", "link_url": "https://status.example.com", "link_external": true, "body": "Check here"}`, string(data))
+			writeJSON(w, Doc{Slug: "status"})
+		}))
+
+		_, err := client.Docs.Create(context.Background(), NewErrorDoc("Oops", "abc123", ""))
+		assert.EqualError(t, err, "invalid doc: error docs require an error code")
+		assert.Equal(t, 0, requests)
+
+		link := NewLinkDoc("Status", "abc123", "https://status.example.com")
+		link.Body = "Check here"
+		doc, err := client.Docs.Create(context.Background(), link)
+		assert.Nil(t, err)
+		assert.Equal(t, "status", doc.Slug)
+		assert.Equal(t, 1, requests)
+	})
+}
+
+func TestDocs_SearchOptions(t *testing.T) {
+	t.Run("sends options and filters hidden results", func(t *testing.T) {
+		var query, version string
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query, version = r.URL.RawQuery, r.Header.Get("x-readme-version")
+			writeJSON(w, DocSearchResults{Results: []*DocSearchResult{
+				{Slug: "auth", Highlight: &DocSearchHighlights{Title: &DocSearchHighlight{Value: "<em>Auth</em>"}}},
+				{Slug: "secret", Hidden: true},
+			}})
+		}))
+
+		results, err := client.Docs.Search(context.Background(), "auth token", SearchOptions{Version: "2.0", Index: SearchIndexReference, PerPage: 5})
+		assert.Nil(t, err)
+
+		assert.Equal(t, "index=reference&perPage=5&search=auth+token", query)
+		assert.Equal(t, "2.0", version)
+		assert.Len(t, results.Results, 1)
+		assert.Equal(t, "<em>Auth</em>", results.Results[0].Highlight.Title.Value)
+		assert.Nil(t, results.Pagination)
+	})
+
+	t.Run("iterates across pages", func(t *testing.T) {
+		pages := [][]*DocSearchResult{{{Slug: "a"}, {Slug: "b"}}, {{Slug: "c"}}}
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			w.Header().Set("x-total-count", "3")
+			if page < len(pages) {
+				w.Header().Set("link", fmt.Sprintf(`</docs/search?page=%d>; rel="next"`, page+1))
+			}
+			writeJSON(w, DocSearchResults{Results: pages[page-1]})
+		}))
+
+		slugs := []string{}
+		it := NewDocSearchIterator(client.Docs, "guide", SearchOptions{PerPage: 2})
+		for it.Next(context.Background()) {
+			slugs = append(slugs, it.Result().Slug)
+		}
+
+		assert.Nil(t, it.Err())
+		assert.Equal(t, []string{"a", "b", "c"}, slugs)
+	})
+
+	t.Run("continues past pages of hidden results", func(t *testing.T) {
+		pages := [][]*DocSearchResult{{{Slug: "a"}}, {{Slug: "b", Hidden: true}}, {{Slug: "c"}}}
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			w.Header().Set("x-total-count", "3")
+			if page < len(pages) {
+				w.Header().Set("link", fmt.Sprintf(`</docs/search?page=%d>; rel="next"`, page+1))
+			}
+			writeJSON(w, DocSearchResults{Results: pages[page-1]})
+		}))
+
+		slugs := []string{}
+		it := NewDocSearchIterator(client.Docs, "guide", SearchOptions{PerPage: 1})
+		for it.Next(context.Background()) {
+			slugs = append(slugs, it.Result().Slug)
+		}
+
+		assert.Nil(t, it.Err())
+		assert.Equal(t, []string{"a", "c"}, slugs)
+	})
+
+	t.Run("falls back to an export when the API is unavailable", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+
+		export := &ProjectExport{Docs: []*Doc{{Slug: "auth", Title: "Authentication", Body: "Use a token."}}}
+
+		results, err := client.Docs.Search(context.Background(), "token", SearchOptions{Fallback: export})
+		assert.Nil(t, err)
+		assert.True(t, results.Offline)
+		assert.Equal(t, "auth", results.Results[0].Slug)
+
+		_, err = client.Docs.Search(context.Background(), "token", SearchOptions{})
+		responseErr := &ResponseError{}
+		assert.True(t, errors.As(err, &responseErr))
+		assert.Equal(t, http.StatusServiceUnavailable, responseErr.StatusCode)
+	})
+
+	t.Run("doesn't fall back when the context is done", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, DocSearchResults{})
+		}))
+
+		export := &ProjectExport{Docs: []*Doc{{Slug: "auth", Title: "Authentication", Body: "Use a token."}}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := client.Docs.Search(ctx, "token", SearchOptions{Fallback: export})
+		assert.ErrorIs(t, err, context.Canceled)
+
+		ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+		_, err = client.Docs.Search(ctx, "token", SearchOptions{Fallback: export})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("falls back when the HTTP client times out", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}))
+		client.http = &http.Client{Timeout: 10 * time.Millisecond}
+
+		export := &ProjectExport{Docs: []*Doc{{Slug: "auth", Title: "Authentication", Body: "Use a token."}}}
+
+		results, err := client.Docs.Search(context.Background(), "token", SearchOptions{Fallback: export})
+		assert.Nil(t, err)
+		assert.True(t, results.Offline)
+	})
+}
@@ -0,0 +1,17 @@
+package readme
+
+import "context"
+
+// Errors allows interactions with the error pages of the project, which are docs of the "error" type
+type Errors interface {
+	List(ctx context.Context) ([]*Doc, error)
+}
+
+type errorPages struct {
+	client *Client
+}
+
+// List the error pages of the project
+func (e *errorPages) List(ctx context.Context) ([]*Doc, error) {
+	return getJSON[[]*Doc](ctx, e.client, "errors", nil)
+}
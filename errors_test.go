@@ -0,0 +1,26 @@
+package readme
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrors_List(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/errors", r.URL.Path)
+		writeJSON(w, []map[string]interface{}{
+			{"title": "Not found", "slug": "not-found", "type": "error", "error": map[string]string{"code": "404"}},
+		})
+	}))
+
+	pages, err := client.Errors.List(context.Background())
+
+	assert.Nil(t, err)
+	assert.Len(t, pages, 1)
+	assert.Equal(t, "not-found", pages[0].Slug)
+	assert.Equal(t, DocTypeError, pages[0].Type)
+	assert.Equal(t, &DocError{Code: "404"}, pages[0].Error)
+}
@@ -0,0 +1,294 @@
+package readme
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ProjectExport is a snapshot of the content of a project version that can be saved and searched
+// offline
+type ProjectExport struct {
+	Version     string        `json:"version"`
+	ExportedAt  time.Time     `json:"exportedAt"`
+	Categories  []*Category   `json:"categories"`
+	Docs        []*Doc        `json:"docs"`
+	CustomPages []*CustomPage `json:"customPages"`
+	Changelogs  []*Changelog  `json:"changelogs"`
+}
+
+// ExportProject fetches the categories and docs of a version (semver, ex. "1.0"), the custom pages
+// and the changelogs of the project
+func (c *Client) ExportProject(ctx context.Context, version string) (*ProjectExport, error) {
+	content, err := c.fetchVersionContent(ctx, version)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch version %s: %w", version, err)
+	}
+
+	pages, err := listAllCustomPages(ctx, c.CustomPages)
+	if err != nil {
+		return nil, fmt.Errorf("could not list custom pages: %w", err)
+	}
+
+	changelogs, err := listAllChangelogs(ctx, c.Changelogs)
+	if err != nil {
+		return nil, fmt.Errorf("could not list changelogs: %w", err)
+	}
+
+	result := &ProjectExport{
+		Version:     version,
+		ExportedAt:  time.Now().UTC(),
+		Categories:  content.categories,
+		Docs:        make([]*Doc, 0, len(content.docs)),
+		CustomPages: pages,
+		Changelogs:  changelogs,
+	}
+
+	for _, slug := range sortedDocSlugs(content.docs) {
+		result.Docs = append(result.Docs, content.docs[slug])
+	}
+
+	return result, nil
+}
+
+// LoadProjectExport reads an export saved with Save
+func LoadProjectExport(path string) (*ProjectExport, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read export: %w", err)
+	}
+
+	result := &ProjectExport{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, fmt.Errorf("could not parse export: %w", err)
+	}
+
+	return result, nil
+}
+
+// Save writes the export to a JSON file
+func (e *ProjectExport) Save(path string) error {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal export: %w", err)
+	}
+
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("could not write export: %w", err)
+	}
+
+	return nil
+}
+
+// searchEntry is a page of an export that can be searched
+type searchEntry struct {
+	index  string
+	title  string
+	slug   string
+	body   string
+	hidden bool
+	path   string
+}
+
+// Search performs a full-text search of the export. Every word of the query must appear in the
+// title or body of a page; matches in titles rank higher. Version is ignored.
+func (e *ProjectExport) Search(search string, opt SearchOptions) *DocSearchResults {
+	terms := searchTerms(search)
+	references := ReferenceCategoryIDs(e.Categories)
+
+	entries := []*searchEntry{}
+	for _, doc := range e.Docs {
+		entry := &searchEntry{index: SearchIndexDocs, title: doc.Title, slug: doc.Slug, body: doc.Body, hidden: doc.Hidden, path: "/docs/"}
+		if IsReferenceDoc(doc, references) {
+			entry.index, entry.path = SearchIndexReference, "/reference/"
+		}
+		entries = append(entries, entry)
+	}
+	for _, page := range e.CustomPages {
+		entries = append(entries, &searchEntry{index: SearchIndexCustomPages, title: page.Title, slug: page.Slug, body: page.Body, hidden: page.Hidden, path: "/page/"})
+	}
+	for _, changelog := range e.Changelogs {
+		entries = append(entries, &searchEntry{index: SearchIndexChangelogs, title: changelog.Title, slug: changelog.Slug, body: changelog.Body, hidden: changelog.Hidden, path: "/changelog/"})
+	}
+
+	type match struct {
+		entry *searchEntry
+		score int
+	}
+
+	matches := []*match{}
+	for _, entry := range entries {
+		if (opt.Index != "" && entry.index != opt.Index) || (entry.hidden && !opt.IncludeHidden) || len(terms) == 0 {
+			continue
+		}
+
+		title, body := searchTerms(entry.title), searchTerms(entry.body)
+		score := 0
+		for _, term := range terms {
+			hits := 3*countTerm(title, term) + countTerm(body, term)
+			if hits == 0 {
+				score = 0
+				break
+			}
+			score += hits
+		}
+
+		if score > 0 {
+			matches = append(matches, &match{entry: entry, score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].entry.title < matches[j].entry.title
+	})
+
+	perPage, page := opt.PerPage, opt.Page
+	if perPage <= 0 {
+		perPage = 20
+	}
+	if page <= 0 {
+		page = 1
+	}
+
+	result := &DocSearchResults{
+		Pagination: &Pagination{TotalCount: len(matches)},
+		Results:    []*DocSearchResult{},
+		Offline:    true,
+	}
+
+	start, end := (page-1)*perPage, page*perPage
+	if end < len(matches) {
+		result.Pagination.Next = fmt.Sprintf("docs/search?page=%d", page+1)
+	} else {
+		end = len(matches)
+	}
+
+	for i := start; i < end; i++ {
+		entry := matches[i].entry
+		result.Results = append(result.Results, &DocSearchResult{
+			IndexName: entry.index,
+			Title:     entry.title,
+			Slug:      entry.slug,
+			Excerpt:   searchSnippet(entry.body, terms),
+			Hidden:    entry.hidden,
+			URL:       entry.path + entry.slug,
+			Highlight: &DocSearchHighlights{
+				Title: &DocSearchHighlight{Value: highlightTerms(entry.title, terms), MatchedWords: terms},
+			},
+		})
+	}
+
+	return result
+}
+
+// searchTerms splits text into lowercase words
+func searchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func countTerm(words []string, term string) int {
+	count := 0
+	for _, word := range words {
+		if word == term {
+			count++
+		}
+	}
+	return count
+}
+
+// searchSnippet returns about 160 characters of the text around the first matching term
+func searchSnippet(text string, terms []string) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+
+	// Match whole words by rune position, since lowercasing can change the length of the text
+	start := -1
+	for _, term := range terms {
+		word := 0
+		for i := 0; i <= len(runes) && start == -1; i++ {
+			if i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsNumber(runes[i])) {
+				continue
+			}
+			if i > word && strings.ToLower(string(runes[word:i])) == term {
+				start = word
+			}
+			word = i + 1
+		}
+		if start != -1 {
+			break
+		}
+	}
+
+	from := start - 40
+	if from < 0 {
+		from = 0
+	}
+	to := from + 160
+	if to > len(runes) {
+		to = len(runes)
+	}
+
+	snippet := string(runes[from:to])
+	if from > 0 {
+		snippet = "…" + snippet
+	}
+	if to < len(runes) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// highlightTerms wraps the words of the text that match a term in <em> tags
+func highlightTerms(text string, terms []string) string {
+	result := strings.Builder{}
+	word := strings.Builder{}
+
+	flush := func() {
+		if word.Len() == 0 {
+			return
+		}
+		if countTerm(terms, strings.ToLower(word.String())) > 0 {
+			result.WriteString("<em>" + word.String() + "</em>")
+		} else {
+			result.WriteString(word.String())
+		}
+		word.Reset()
+	}
+
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			word.WriteRune(r)
+			continue
+		}
+		flush()
+		result.WriteRune(r)
+	}
+	flush()
+
+	return result.String()
+}
+
+// ReferenceCategoryIDs returns the IDs of the API reference categories, for use with IsReferenceDoc
+func ReferenceCategoryIDs(categories []*Category) map[string]bool {
+	result := make(map[string]bool)
+	for _, category := range categories {
+		if category.Reference || category.IsAPI {
+			result[category.ID] = true
+		}
+	}
+	return result
+}
+
+// IsReferenceDoc reports whether the doc is an API reference page
+func IsReferenceDoc(doc *Doc, references map[string]bool) bool {
+	return doc.IsReference || doc.IsAPI || references[doc.Category]
+}
@@ -0,0 +1,112 @@
+package readme
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_ExportProject(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, DefaultBasePath+"/")
+
+		switch {
+		case path == "categories":
+			writePage(w, 1, []*Category{{Slug: "guides", ID: "c1"}})
+		case path == "categories/guides/docs":
+			writeJSON(w, []*CategoryDoc{{Slug: "setup"}})
+		case path == "docs/setup":
+			writeJSON(w, Doc{Slug: "setup", Title: "Setup", Body: "Install it"})
+		case path == "custompages":
+			writePage(w, 1, []*CustomPage{{Slug: "about", Title: "About"}})
+		case path == "changelogs":
+			writePage(w, 1, []*Changelog{{Slug: "v1", Title: "v1"}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	export, err := client.ExportProject(context.Background(), "1.0")
+	assert.Nil(t, err)
+
+	path := filepath.Join(t.TempDir(), "export.json")
+	assert.Nil(t, export.Save(path))
+
+	loaded, err := LoadProjectExport(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "1.0", loaded.Version)
+	assert.Equal(t, "Install it", loaded.Docs[0].Body)
+	assert.Equal(t, "about", loaded.CustomPages[0].Slug)
+	assert.Equal(t, "v1", loaded.Changelogs[0].Slug)
+}
+
+func TestProjectExport_Search(t *testing.T) {
+	export := &ProjectExport{
+		Categories: []*Category{{ID: "c1"}, {ID: "c2", Reference: true}},
+		Docs: []*Doc{
+			{Slug: "tokens", Title: "API Tokens", Category: "c1", Body: "Create a token in the dashboard."},
+			{Slug: "auth", Title: "Authentication", Category: "c1", Body: "Send your API token in the Authorization header."},
+			{Slug: "create-token", Title: "Create token", Category: "c2", Body: "Creates an API token."},
+			{Slug: "internal", Title: "Internal token", Category: "c1", Hidden: true},
+		},
+		Changelogs: []*Changelog{{Slug: "tokens-v2", Title: "Token rotation", Body: "API tokens rotate"}},
+	}
+
+	t.Run("ranks title matches first", func(t *testing.T) {
+		results := export.Search("API token", SearchOptions{})
+
+		slugs := []string{}
+		for _, result := range results.Results {
+			slugs = append(slugs, result.Slug)
+		}
+
+		assert.Equal(t, []string{"create-token", "tokens", "tokens-v2", "auth"}, slugs)
+		assert.Equal(t, "/reference/create-token", results.Results[0].URL)
+		assert.Equal(t, SearchIndexReference, results.Results[0].IndexName)
+		assert.Equal(t, "Create <em>token</em>", results.Results[0].Highlight.Title.Value)
+		assert.True(t, results.Offline)
+	})
+
+	t.Run("filters by index and hidden state", func(t *testing.T) {
+		results := export.Search("token", SearchOptions{Index: SearchIndexDocs, IncludeHidden: true})
+		assert.Equal(t, 3, results.Pagination.TotalCount)
+
+		results = export.Search("token", SearchOptions{Index: SearchIndexCustomPages})
+		assert.Len(t, results.Results, 0)
+	})
+
+	t.Run("pages results", func(t *testing.T) {
+		first := export.Search("token", SearchOptions{PerPage: 3})
+		assert.Len(t, first.Results, 3)
+		assert.NotEmpty(t, first.Pagination.Next)
+
+		second := export.Search("token", SearchOptions{PerPage: 3, Page: 2})
+		assert.Len(t, second.Results, 1)
+		assert.Empty(t, second.Pagination.Next)
+	})
+
+	t.Run("builds snippets of text that changes length when lowercased", func(t *testing.T) {
+		unicode := &ProjectExport{Docs: []*Doc{
+			{Slug: "wide", Title: "Wide", Body: strings.Repeat("Ⱥ", 60) + " token " + strings.Repeat("Ⱥ", 200)},
+		}}
+
+		results := unicode.Search("token", SearchOptions{})
+
+		assert.Len(t, results.Results, 1)
+		assert.Equal(t, "…"+strings.Repeat("Ⱥ", 39)+" token "+strings.Repeat("Ⱥ", 114)+"…", results.Results[0].Excerpt)
+	})
+
+	t.Run("matches whole words in snippets", func(t *testing.T) {
+		words := &ProjectExport{Docs: []*Doc{
+			{Slug: "words", Title: "Words", Body: "Tokenizers aside, " + strings.Repeat("x ", 40) + "use a token."},
+		}}
+
+		excerpt := words.Search("token", SearchOptions{}).Results[0].Excerpt
+		assert.True(t, strings.HasPrefix(excerpt, "…"))
+		assert.True(t, strings.HasSuffix(excerpt, "use a token."))
+	})
+}
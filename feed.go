@@ -0,0 +1,390 @@
+package readme
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// FeedFormatRSS renders an RSS 2.0 feed
+	FeedFormatRSS = "rss"
+
+	// FeedFormatAtom renders an Atom 1.0 feed
+	FeedFormatAtom = "atom"
+
+	// FeedFormatJSON renders a JSON Feed 1.1 feed
+	FeedFormatJSON = "json"
+)
+
+// ChangelogFeedOptions are the options available when generating a changelog feed
+type ChangelogFeedOptions struct {
+	// Title of the feed. Defaults to the project name followed by "Changelog".
+	Title string
+
+	// Description of the feed
+	Description string
+
+	// FeedURL is the public URL the feed is served from, if any
+	FeedURL string
+
+	// Limit is the maximum number of entries in the feed. Zero includes every entry.
+	Limit int
+}
+
+// ChangelogFeed is a format independent feed of the published changelog entries of a project
+type ChangelogFeed struct {
+	Title       string
+	Description string
+	Link        string
+	FeedURL     string
+	Updated     time.Time
+	Entries     []*ChangelogFeedEntry
+}
+
+// ChangelogFeedEntry is a single changelog entry of a ChangelogFeed
+type ChangelogFeedEntry struct {
+	ID        string
+	Title     string
+	Link      string
+	Type      string
+	HTML      string
+	Published time.Time
+}
+
+// NewChangelogFeed pages through all the changelogs of the project and builds a feed from the
+// entries that aren't hidden, newest first
+func NewChangelogFeed(ctx context.Context, client *Client, opt ChangelogFeedOptions) (*ChangelogFeed, error) {
+	project, err := client.Project.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get project: %w", err)
+	}
+
+	changelogs, err := listAllChangelogs(ctx, client.Changelogs)
+	if err != nil {
+		return nil, fmt.Errorf("could not list changelogs: %w", err)
+	}
+
+	baseURL := strings.TrimSuffix(project.BaseURL, "/")
+	feed := &ChangelogFeed{
+		Title:       opt.Title,
+		Description: opt.Description,
+		Link:        baseURL + "/changelog",
+		FeedURL:     opt.FeedURL,
+		Entries:     []*ChangelogFeedEntry{},
+	}
+
+	if feed.Title == "" {
+		feed.Title = strings.TrimSpace(project.Name + " Changelog")
+	}
+
+	for _, changelog := range changelogs {
+		if changelog.Hidden {
+			continue
+		}
+
+		published, err := time.Parse(time.RFC3339, changelog.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse createdAt of changelog %s: %w", changelog.Slug, err)
+		}
+
+		link := feed.Link + "/" + changelog.Slug
+		feed.Entries = append(feed.Entries, &ChangelogFeedEntry{
+			ID:        link,
+			Title:     changelog.Title,
+			Link:      link,
+			Type:      changelog.Type,
+			HTML:      changelog.Html,
+			Published: published.UTC(),
+		})
+	}
+
+	sort.SliceStable(feed.Entries, func(i, j int) bool {
+		return feed.Entries[i].Published.After(feed.Entries[j].Published)
+	})
+
+	if opt.Limit > 0 && len(feed.Entries) > opt.Limit {
+		feed.Entries = feed.Entries[:opt.Limit]
+	}
+
+	if len(feed.Entries) > 0 {
+		feed.Updated = feed.Entries[0].Published
+	}
+
+	return feed, nil
+}
+
+// Write renders the feed in the specified format (FeedFormatRSS, FeedFormatAtom or FeedFormatJSON)
+func (f *ChangelogFeed) Write(w io.Writer, format string) error {
+	switch format {
+	case FeedFormatRSS:
+		return f.WriteRSS(w)
+	case FeedFormatAtom:
+		return f.WriteAtom(w)
+	case FeedFormatJSON:
+		return f.WriteJSON(w)
+	default:
+		return fmt.Errorf("unknown feed format %q", format)
+	}
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Description   string     `xml:"description"`
+	LastBuildDate string     `xml:"lastBuildDate,omitempty"`
+	Items         []*rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Category    string  `xml:"category,omitempty"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// WriteRSS renders the feed as RSS 2.0
+func (f *ChangelogFeed) WriteRSS(w io.Writer) error {
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       f.Title,
+			Link:        f.Link,
+			Description: f.Description,
+			Items:       make([]*rssItem, 0, len(f.Entries)),
+		},
+	}
+
+	if !f.Updated.IsZero() {
+		feed.Channel.LastBuildDate = f.Updated.Format(time.RFC1123Z)
+	}
+
+	for _, entry := range f.Entries {
+		feed.Channel.Items = append(feed.Channel.Items, &rssItem{
+			Title:       entry.Title,
+			Link:        entry.Link,
+			GUID:        rssGUID{IsPermaLink: true, Value: entry.ID},
+			PubDate:     entry.Published.Format(time.RFC1123Z),
+			Category:    entry.Type,
+			Description: entry.HTML,
+		})
+	}
+
+	return writeXML(w, feed)
+}
+
+type atomFeed struct {
+	XMLName xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string       `xml:"title"`
+	ID      string       `xml:"id"`
+	Updated string       `xml:"updated"`
+	Links   []*atomLink  `xml:"link"`
+	Entries []*atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title     string        `xml:"title"`
+	ID        string        `xml:"id"`
+	Link      *atomLink     `xml:"link"`
+	Published string        `xml:"published"`
+	Updated   string        `xml:"updated"`
+	Category  *atomCategory `xml:"category,omitempty"`
+	Content   atomContent   `xml:"content"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteAtom renders the feed as Atom 1.0
+func (f *ChangelogFeed) WriteAtom(w io.Writer) error {
+	feed := atomFeed{
+		Title:   f.Title,
+		ID:      f.Link,
+		Updated: f.Updated.Format(time.RFC3339),
+		Links:   []*atomLink{{Href: f.Link, Rel: "alternate"}},
+		Entries: make([]*atomEntry, 0, len(f.Entries)),
+	}
+
+	if f.FeedURL != "" {
+		feed.Links = append(feed.Links, &atomLink{Href: f.FeedURL, Rel: "self"})
+	}
+
+	for _, entry := range f.Entries {
+		atom := &atomEntry{
+			Title:     entry.Title,
+			ID:        entry.ID,
+			Link:      &atomLink{Href: entry.Link, Rel: "alternate"},
+			Published: entry.Published.Format(time.RFC3339),
+			Updated:   entry.Published.Format(time.RFC3339),
+			Content:   atomContent{Type: "html", Value: entry.HTML},
+		}
+
+		if entry.Type != "" {
+			atom.Category = &atomCategory{Term: entry.Type}
+		}
+
+		feed.Entries = append(feed.Entries, atom)
+	}
+
+	return writeXML(w, feed)
+}
+
+type jsonFeed struct {
+	Version     string          `json:"version"`
+	Title       string          `json:"title"`
+	HomePageURL string          `json:"home_page_url,omitempty"`
+	FeedURL     string          `json:"feed_url,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Items       []*jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	ContentHTML   string   `json:"content_html"`
+	DatePublished string   `json:"date_published"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+// WriteJSON renders the feed as JSON Feed 1.1
+func (f *ChangelogFeed) WriteJSON(w io.Writer) error {
+	feed := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		FeedURL:     f.FeedURL,
+		Description: f.Description,
+		Items:       make([]*jsonFeedItem, 0, len(f.Entries)),
+	}
+
+	for _, entry := range f.Entries {
+		item := &jsonFeedItem{
+			ID:            entry.ID,
+			URL:           entry.Link,
+			Title:         entry.Title,
+			ContentHTML:   entry.HTML,
+			DatePublished: entry.Published.Format(time.RFC3339),
+		}
+
+		if entry.Type != "" {
+			item.Tags = []string{entry.Type}
+		}
+
+		feed.Items = append(feed.Items, item)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(feed)
+}
+
+func writeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("could not encode feed: %w", err)
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+var feedContentTypes = map[string]string{
+	FeedFormatRSS:  "application/rss+xml; charset=utf-8",
+	FeedFormatAtom: "application/atom+xml; charset=utf-8",
+	FeedFormatJSON: "application/feed+json; charset=utf-8",
+}
+
+// ChangelogFeedHandler is an http.Handler that serves the changelog feed of a project
+type ChangelogFeedHandler struct {
+	Client  *Client
+	Options ChangelogFeedOptions
+
+	// Format of the feed. When empty, the format is taken from the "format" query parameter and
+	// defaults to FeedFormatRSS.
+	Format string
+
+	// OnError is called with the details of a failure, which aren't sent to the client. Defaults to
+	// logging the error.
+	OnError func(err error)
+}
+
+// ServeHTTP fetches the changelogs and renders the feed
+func (h *ChangelogFeedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	format := h.Format
+	if format == "" {
+		format = r.URL.Query().Get("format")
+	}
+	if format == "" {
+		format = FeedFormatRSS
+	}
+
+	contentType, ok := feedContentTypes[format]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown feed format %q", format), http.StatusBadRequest)
+		return
+	}
+
+	feed, err := NewChangelogFeed(r.Context(), h.Client, h.Options)
+	if err != nil {
+		h.error(err)
+		http.Error(w, "could not fetch the changelog", http.StatusBadGateway)
+		return
+	}
+
+	// Render before writing anything so that a failure can still be reported with its status
+	var body bytes.Buffer
+	if err := feed.Write(&body, format); err != nil {
+		h.error(err)
+		http.Error(w, "could not render the feed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("content-type", contentType)
+	body.WriteTo(w)
+}
+
+func (h *ChangelogFeedHandler) error(err error) {
+	if h.OnError != nil {
+		h.OnError(err)
+		return
+	}
+	log.Printf("[ERROR] could not serve changelog feed: %v", err)
+}
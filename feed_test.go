@@ -0,0 +1,121 @@
+package readme
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newFeedTestClient(t *testing.T) *Client {
+	return newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case DefaultBasePath + "/":
+			writeJSON(w, ProjectMetadata{Name: "Petstore", BaseURL: "https://petstore.readme.io/"})
+		case DefaultBasePath + "/changelogs":
+			writePage(w, 3, []*Changelog{
+				{Title: "Older", Slug: "older", Type: ChangelogTypeFixed, Html: "<p>fixed</p>", CreatedAt: "2021-08-01T10:00:00.000Z"},
+				{Title: "Draft", Slug: "draft", Hidden: true, CreatedAt: "2021-10-01T10:00:00.000Z"},
+				{Title: "Newer & shinier", Slug: "newer", Type: ChangelogTypeAdded, Html: "<p>added</p>", CreatedAt: "2021-09-01T10:00:00.000Z"},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestChangelogFeed(t *testing.T) {
+	client := newFeedTestClient(t)
+
+	feed, err := NewChangelogFeed(context.Background(), client, ChangelogFeedOptions{})
+	assert.Nil(t, err)
+
+	t.Run("filters hidden entries and sorts newest first", func(t *testing.T) {
+		assert.Equal(t, "Petstore Changelog", feed.Title)
+		assert.Equal(t, "https://petstore.readme.io/changelog", feed.Link)
+		assert.Len(t, feed.Entries, 2)
+		assert.Equal(t, "newer", feed.Entries[0].Link[strings.LastIndex(feed.Entries[0].Link, "/")+1:])
+		assert.Equal(t, feed.Entries[0].Published, feed.Updated)
+	})
+
+	t.Run("renders rss", func(t *testing.T) {
+		output := new(bytes.Buffer)
+		assert.Nil(t, feed.WriteRSS(output))
+
+		assert.Contains(t, output.String(), `<rss version="2.0">`)
+		assert.Contains(t, output.String(), "<title>Newer &amp; shinier</title>")
+		assert.Contains(t, output.String(), "<pubDate>Wed, 01 Sep 2021 10:00:00 +0000</pubDate>")
+		assert.Contains(t, output.String(), "<category>added</category>")
+		assert.Contains(t, output.String(), "<description>&lt;p&gt;added&lt;/p&gt;</description>")
+	})
+
+	t.Run("renders atom", func(t *testing.T) {
+		output := new(bytes.Buffer)
+		assert.Nil(t, feed.WriteAtom(output))
+
+		assert.Contains(t, output.String(), `<feed xmlns="http://www.w3.org/2005/Atom">`)
+		assert.Contains(t, output.String(), "<updated>2021-09-01T10:00:00Z</updated>")
+		assert.Contains(t, output.String(), `<link href="https://petstore.readme.io/changelog/older" rel="alternate"></link>`)
+		assert.Contains(t, output.String(), `<content type="html">&lt;p&gt;fixed&lt;/p&gt;</content>`)
+	})
+
+	t.Run("renders json feed", func(t *testing.T) {
+		output := new(bytes.Buffer)
+		assert.Nil(t, feed.WriteJSON(output))
+
+		result := jsonFeed{}
+		assert.Nil(t, json.Unmarshal(output.Bytes(), &result))
+		assert.Equal(t, "https://jsonfeed.org/version/1.1", result.Version)
+		assert.Len(t, result.Items, 2)
+		assert.Equal(t, "<p>added</p>", result.Items[0].ContentHTML)
+		assert.Equal(t, []string{ChangelogTypeAdded}, result.Items[0].Tags)
+	})
+}
+
+func TestChangelogFeedHandler(t *testing.T) {
+	handler := &ChangelogFeedHandler{Client: newFeedTestClient(t)}
+
+	t.Run("serves the requested format", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/feed?format=atom", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "application/atom+xml; charset=utf-8", recorder.Header().Get("content-type"))
+	})
+
+	t.Run("rejects unknown formats", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/feed?format=csv", nil))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `unknown feed format "csv"`)
+	})
+
+	t.Run("hides the details of upstream errors", func(t *testing.T) {
+		errs := []error{}
+		failing := &ChangelogFeedHandler{
+			Client: newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("content-type", "application/json")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write([]byte(`{"error":"INTERNAL","message":"secret upstream detail"}`))
+			})),
+			OnError: func(err error) { errs = append(errs, err) },
+		}
+
+		recorder := httptest.NewRecorder()
+		failing.ServeHTTP(recorder, httptest.NewRequest("GET", "/feed", nil))
+
+		assert.Equal(t, http.StatusBadGateway, recorder.Code)
+		assert.Equal(t, "text/plain; charset=utf-8", recorder.Header().Get("content-type"))
+		assert.Equal(t, "could not fetch the changelog\n", recorder.Body.String())
+
+		if assert.Len(t, errs, 1) {
+			assert.Contains(t, errs[0].Error(), "secret upstream detail")
+		}
+	})
+}
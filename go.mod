@@ -1,14 +1,16 @@
 module github.com/brandonc/go-readme
 
-go 1.17
+go 1.18
 
-require github.com/stretchr/testify v1.7.0
+require (
+	github.com/brandonc/go-weblinks v0.0.0-20210903181635-496fa4baa2cd
+	github.com/google/go-querystring v1.1.0
+	github.com/pmezard/go-difflib v1.0.0
+	github.com/stretchr/testify v1.7.0
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
+)
 
 require (
-	github.com/brandonc/go-weblinks v0.0.0-20210903181635-496fa4baa2cd // indirect
 	github.com/davecgh/go-spew v1.1.0 // indirect
-	github.com/google/go-querystring v1.1.0 // indirect
 	github.com/hashicorp/logutils v1.0.0 // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
 )
@@ -0,0 +1,156 @@
+package readme
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// UserKey is a set of credentials a logged in user can pick from in the API explorer
+type UserKey struct {
+	// Name labels the key in the explorer, ex. "Production"
+	Name string `json:"name,omitempty"`
+
+	// ApiKey fills API key security schemes
+	ApiKey string `json:"apiKey,omitempty"`
+
+	// User and Pass fill basic auth security schemes
+	User string `json:"user,omitempty"`
+	Pass string `json:"pass,omitempty"`
+
+	// Variables are custom variables of the key, ex. {"region": "eu"}, used as server variables and
+	// in <<variable>> references
+	Variables map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON flattens the variables of the key into the key object
+func (k *UserKey) MarshalJSON() ([]byte, error) {
+	type key UserKey
+	return mergeVariables(k.Variables, (*key)(k))
+}
+
+// UserData is the data of a user logged in to personalized docs
+type UserData struct {
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+
+	// Keys are the credentials shown in the API explorer
+	Keys []*UserKey `json:"keys,omitempty"`
+
+	// IsReadOnly prevents the user from editing the keys
+	IsReadOnly bool `json:"isReadOnly,omitempty"`
+
+	// Variables are custom variables of the user, ex. {"plan": "enterprise"}, used in <<variable>>
+	// references of docs
+	Variables map[string]interface{} `json:"-"`
+
+	// Redirect is the path the user is sent to after logging in, ex. "/reference/list-pets"
+	Redirect string `json:"-"`
+
+	// IssuedAt is the time the token is signed at. Defaults to the current time.
+	IssuedAt time.Time `json:"-"`
+
+	// ExpiresAt, when set, is the time after which the token can't be used to log in
+	ExpiresAt time.Time `json:"-"`
+}
+
+// userClaims is the payload of a user token
+type userClaims struct {
+	*UserData
+	Version   int   `json:"version"`
+	IssuedAt  int64 `json:"iat"`
+	ExpiresAt int64 `json:"exp,omitempty"`
+}
+
+// MarshalJSON flattens the variables of the user into the user object
+func (u *userClaims) MarshalJSON() ([]byte, error) {
+	type claims userClaims
+	return mergeVariables(u.UserData.Variables, (*claims)(u))
+}
+
+// mergeVariables marshals v and adds the variables to the resulting object. Fields of v take
+// precedence over variables with the same name.
+func mergeVariables(variables map[string]interface{}, v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(variables) == 0 {
+		return data, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{}
+	for name, value := range variables {
+		result[name] = value
+	}
+	for name, value := range fields {
+		result[name] = value
+	}
+
+	return json.Marshal(result)
+}
+
+// Token returns the HS256 JSON web token that logs the user in to personalized docs, signed with
+// the JWT secret of the project, see ProjectMetadata.JwtSecret
+func (u *UserData) Token(secret string) (string, error) {
+	if secret == "" {
+		return "", errors.New("a JWT secret is required to sign user data")
+	}
+
+	issuedAt := u.IssuedAt
+	if issuedAt.IsZero() {
+		issuedAt = time.Now()
+	}
+
+	claims := &userClaims{UserData: u, Version: 1, IssuedAt: issuedAt.Unix()}
+	if !u.ExpiresAt.IsZero() {
+		claims.ExpiresAt = u.ExpiresAt.Unix()
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal user data: %w", err)
+	}
+
+	encoding := base64.RawURLEncoding
+	unsigned := encoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + encoding.EncodeToString(payload)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+
+	return unsigned + "." + encoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// LoginURL returns the URL of the project's hub that logs the user in to personalized docs with an
+// auth_token signed with the JWT secret of the project. The user is sent to user.Redirect, if set.
+func (p *ProjectMetadata) LoginURL(user *UserData) (string, error) {
+	if p.BaseURL == "" {
+		return "", errors.New("the project has no base URL")
+	}
+
+	u, err := url.Parse(p.BaseURL)
+	if err != nil {
+		return "", fmt.Errorf("could not parse base URL: %w", err)
+	}
+
+	token, err := user.Token(p.JwtSecret)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	query.Set("auth_token", token)
+	if user.Redirect != "" {
+		query.Set("redirect", user.Redirect)
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
@@ -0,0 +1,113 @@
+package readme
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// decodeToken checks the HS256 signature of a token and returns its header and payload
+func decodeToken(t *testing.T, token string, secret string) (map[string]interface{}, map[string]interface{}) {
+	parts := strings.Split(token, ".")
+	assert.Len(t, parts, 3)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), parts[2], "signature")
+
+	decode := func(part string) map[string]interface{} {
+		data, err := base64.RawURLEncoding.DecodeString(part)
+		assert.Nil(t, err)
+
+		result := map[string]interface{}{}
+		assert.Nil(t, json.Unmarshal(data, &result))
+		return result
+	}
+
+	return decode(parts[0]), decode(parts[1])
+}
+
+func TestUserData_Token(t *testing.T) {
+	issuedAt := time.Date(2021, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("signs the user data", func(t *testing.T) {
+		user := &UserData{
+			Name:  "Ada",
+			Email: "ada@example.com",
+			Keys: []*UserKey{
+				{Name: "Production", ApiKey: "live_123", Variables: map[string]interface{}{"region": "eu"}},
+				{Name: "Sandbox", User: "ada", Pass: "secret"},
+			},
+			Variables: map[string]interface{}{"plan": "enterprise", "name": "ignored"},
+			IssuedAt:  issuedAt,
+			ExpiresAt: issuedAt.Add(time.Hour),
+		}
+
+		token, err := user.Token("jwtSecret")
+		assert.Nil(t, err)
+
+		header, payload := decodeToken(t, token, "jwtSecret")
+		assert.Equal(t, map[string]interface{}{"alg": "HS256", "typ": "JWT"}, header)
+		assert.Equal(t, map[string]interface{}{
+			"name":  "Ada",
+			"email": "ada@example.com",
+			"keys": []interface{}{
+				map[string]interface{}{"name": "Production", "apiKey": "live_123", "region": "eu"},
+				map[string]interface{}{"name": "Sandbox", "user": "ada", "pass": "secret"},
+			},
+			"plan":    "enterprise",
+			"version": float64(1),
+			"iat":     float64(issuedAt.Unix()),
+			"exp":     float64(issuedAt.Add(time.Hour).Unix()),
+		}, payload)
+	})
+
+	t.Run("defaults the issue time to now", func(t *testing.T) {
+		token, err := (&UserData{Email: "ada@example.com"}).Token("jwtSecret")
+		assert.Nil(t, err)
+
+		_, payload := decodeToken(t, token, "jwtSecret")
+		assert.InDelta(t, time.Now().Unix(), payload["iat"], 5)
+		assert.NotContains(t, payload, "exp")
+	})
+
+	t.Run("requires a secret", func(t *testing.T) {
+		_, err := (&UserData{}).Token("")
+		assert.EqualError(t, err, "a JWT secret is required to sign user data")
+	})
+}
+
+func TestProjectMetadata_LoginURL(t *testing.T) {
+	project := &ProjectMetadata{BaseURL: "https://docs.example.com", JwtSecret: "jwtSecret"}
+
+	t.Run("adds the auth token and redirect", func(t *testing.T) {
+		login, err := project.LoginURL(&UserData{Name: "Ada", Redirect: "/reference/list-pets"})
+		assert.Nil(t, err)
+
+		u, err := url.Parse(login)
+		assert.Nil(t, err)
+		assert.Equal(t, "docs.example.com", u.Host)
+		assert.Equal(t, "/reference/list-pets", u.Query().Get("redirect"))
+
+		_, payload := decodeToken(t, u.Query().Get("auth_token"), "jwtSecret")
+		assert.Equal(t, "Ada", payload["name"])
+		assert.NotContains(t, payload, "redirect")
+	})
+
+	t.Run("requires a base URL", func(t *testing.T) {
+		_, err := (&ProjectMetadata{JwtSecret: "jwtSecret"}).LoginURL(&UserData{})
+		assert.EqualError(t, err, "the project has no base URL")
+	})
+
+	t.Run("requires a secret", func(t *testing.T) {
+		_, err := (&ProjectMetadata{BaseURL: "https://docs.example.com"}).LoginURL(&UserData{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,201 @@
+package readme
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// KeepAChangelogTypes maps the section headings of https://keepachangelog.com onto changelog types
+var KeepAChangelogTypes = map[string]string{
+	"added":      ChangelogTypeAdded,
+	"changed":    ChangelogTypeImproved,
+	"fixed":      ChangelogTypeFixed,
+	"security":   ChangelogTypeFixed,
+	"deprecated": ChangelogTypeDeprecated,
+	"removed":    ChangelogTypeRemoved,
+}
+
+var (
+	keepAChangelogRelease = regexp.MustCompile(`^##\s+\[?([^\]\s]+)\]?(?:\s+-\s+(\S+))?`)
+	keepAChangelogSection = regexp.MustCompile(`^###\s+(.+?)\s*$`)
+	keepAChangelogItem    = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+	keepAChangelogLinkDef = regexp.MustCompile(`^\[[^\]]+\]:\s`)
+)
+
+// ParseKeepAChangelog parses a CHANGELOG.md in Keep a Changelog format into releases, newest first.
+// Each release is titled by its version. Sections with headings that are not in KeepAChangelogTypes
+// are returned with an empty Type.
+func ParseKeepAChangelog(r io.Reader) ([]*ChangelogRelease, error) {
+	result := []*ChangelogRelease{}
+
+	var release *ChangelogRelease
+	var section *ChangelogSection
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t")
+
+		if match := keepAChangelogRelease.FindStringSubmatch(line); match != nil {
+			release = &ChangelogRelease{
+				Title:    match[1],
+				Version:  match[1],
+				Date:     match[2],
+				Sections: []*ChangelogSection{},
+			}
+			section = nil
+			result = append(result, release)
+			continue
+		}
+
+		if release == nil || keepAChangelogLinkDef.MatchString(line) {
+			continue
+		}
+
+		if match := keepAChangelogSection.FindStringSubmatch(line); match != nil {
+			heading := match[1]
+			section = release.section(KeepAChangelogTypes[strings.ToLower(heading)], heading)
+			continue
+		}
+
+		if section == nil || strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if match := keepAChangelogItem.FindStringSubmatch(line); match != nil {
+			section.Items = append(section.Items, match[1])
+		} else if len(section.Items) > 0 {
+			// continuation of a wrapped item
+			last := len(section.Items) - 1
+			section.Items[last] += " " + strings.TrimSpace(line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read changelog: %w", err)
+	}
+
+	return result, nil
+}
+
+// KeepAChangelogSyncOptions are the options available when syncing releases into ReadMe changelogs
+type KeepAChangelogSyncOptions struct {
+	// TitleFormat formats the release version into the changelog title, ex. "v%s". Defaults to "%s".
+	TitleFormat string
+
+	// Template renders the changelog body from a ChangelogRelease. Defaults to DefaultChangelogTemplate.
+	Template *template.Template
+
+	// IncludeUnreleased syncs the "Unreleased" section as well
+	IncludeUnreleased bool
+
+	// Hidden creates new changelog entries hidden
+	Hidden *bool
+
+	// DryRun reports what would change without creating or updating any changelog entries
+	DryRun bool
+}
+
+// ChangelogSyncResult lists the slugs of the changelog entries affected by a sync. On a dry run,
+// Created lists titles instead, since ReadMe assigns the slug when the entry is created.
+type ChangelogSyncResult struct {
+	Created []string
+	Updated []string
+	Skipped []string
+}
+
+// SyncKeepAChangelog creates a changelog entry for each release that doesn't exist yet and updates
+// entries whose type or body were edited. Entries are matched by their exact title, so syncing the
+// same releases again changes nothing.
+func SyncKeepAChangelog(ctx context.Context, changelogs Changelogs, releases []*ChangelogRelease, opt KeepAChangelogSyncOptions) (*ChangelogSyncResult, error) {
+	existing, err := listAllChangelogs(ctx, changelogs)
+	if err != nil {
+		return nil, fmt.Errorf("could not list changelogs: %w", err)
+	}
+
+	// Titles are matched rather than slugs, since similar versions such as "1.0.0" and "10.0" can
+	// end up with the same slug
+	byTitle := make(map[string]*Changelog, len(existing))
+	for _, changelog := range existing {
+		byTitle[changelog.Title] = changelog
+	}
+
+	titleFormat := opt.TitleFormat
+	if titleFormat == "" {
+		titleFormat = "%s"
+	}
+
+	result := &ChangelogSyncResult{
+		Created: []string{},
+		Updated: []string{},
+		Skipped: []string{},
+	}
+
+	for _, release := range releases {
+		if strings.EqualFold(release.Version, "unreleased") && !opt.IncludeUnreleased {
+			continue
+		}
+
+		titled := *release
+		titled.Title = fmt.Sprintf(titleFormat, release.Version)
+
+		create, err := titled.CreateOptions(opt.Template)
+		if err != nil {
+			return result, err
+		}
+		create.Hidden = opt.Hidden
+
+		current, ok := byTitle[create.Title]
+
+		switch {
+		case !ok && opt.DryRun:
+			result.Created = append(result.Created, create.Title)
+
+		case !ok:
+			created, err := changelogs.Create(ctx, *create)
+			if err != nil {
+				return result, fmt.Errorf("could not create changelog %q: %w", create.Title, err)
+			}
+			byTitle[created.Title] = created
+			result.Created = append(result.Created, created.Slug)
+
+		case current.Type != create.Type || strings.TrimSpace(current.Body) != strings.TrimSpace(create.Body):
+			if !opt.DryRun {
+				update := ChangelogUpdateOptions{
+					Title: create.Title,
+					Body:  create.Body,
+					Type:  create.Type,
+				}
+
+				if _, err := changelogs.Update(ctx, current.Slug, update); err != nil {
+					return result, fmt.Errorf("could not update changelog %q: %w", current.Slug, err)
+				}
+			}
+			result.Updated = append(result.Updated, current.Slug)
+
+		default:
+			result.Skipped = append(result.Skipped, current.Slug)
+		}
+	}
+
+	return result, nil
+}
+
+var (
+	slugSeparators = regexp.MustCompile(`[\s_]+`)
+	slugInvalid    = regexp.MustCompile(`[^a-z0-9-]`)
+	slugDashes     = regexp.MustCompile(`-{2,}`)
+)
+
+// slugify derives a slug from a title the same way ReadMe does when a page is created
+func slugify(title string) string {
+	slug := strings.ToLower(strings.TrimSpace(title))
+	slug = slugSeparators.ReplaceAllString(slug, "-")
+	slug = slugInvalid.ReplaceAllString(slug, "")
+	slug = slugDashes.ReplaceAllString(slug, "-")
+	return strings.Trim(slug, "-")
+}
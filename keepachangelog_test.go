@@ -0,0 +1,142 @@
+package readme
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeepAChangelog(t *testing.T) {
+	t.Run("splits releases and sections", func(t *testing.T) {
+		file, err := os.Open("./fixtures/CHANGELOG.md")
+		assert.Nil(t, err)
+		defer file.Close()
+
+		releases, err := ParseKeepAChangelog(file)
+		assert.Nil(t, err)
+
+		assert.Len(t, releases, 3)
+		assert.Equal(t, "Unreleased", releases[0].Version)
+
+		assert.Equal(t, "1.1.0", releases[1].Version)
+		assert.Equal(t, "2021-09-01", releases[1].Date)
+		assert.Len(t, releases[1].Sections, 2)
+		assert.Equal(t, ChangelogTypeAdded, releases[1].Sections[0].Type)
+		assert.Equal(t, []string{"Search across versions", "Changelog feeds that wrap onto a second line"}, releases[1].Sections[0].Items)
+		assert.Equal(t, ChangelogTypeFixed, releases[1].Sections[1].Type)
+		assert.Equal(t, "Security", releases[1].Sections[1].Heading)
+		assert.Equal(t, ChangelogTypeAdded, releases[1].Type())
+
+		assert.Equal(t, ChangelogTypeRemoved, releases[2].Type())
+	})
+}
+
+// newChangelogSyncClient serves the existing changelogs and records the requests. Created entries get
+// their slug from the server, like ReadMe does.
+func newChangelogSyncClient(t *testing.T, existing []*Changelog, requests *[]string) *Client {
+	return newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.Method+" "+r.URL.Path)
+
+		switch r.Method {
+		case "GET":
+			writePage(w, len(existing), existing)
+		default:
+			changelog := Changelog{}
+			json.NewDecoder(r.Body).Decode(&changelog)
+			if r.Method == "POST" {
+				changelog.Slug = strings.ReplaceAll(changelog.Title, ".", "-")
+			}
+			writeJSON(w, changelog)
+		}
+	}))
+}
+
+func TestSyncKeepAChangelog(t *testing.T) {
+	t.Run("creates, updates and skips by title", func(t *testing.T) {
+		releases, err := ParseKeepAChangelog(strings.NewReader(`## [1.2.0] - 2021-10-01
+### Fixed
+- A bug
+
+## [1.1.0] - 2021-09-01
+### Added
+- Search
+
+## [1.0.0] - 2021-08-01
+### Removed
+- Legacy endpoints
+`))
+		assert.Nil(t, err)
+
+		existing := []*Changelog{
+			{Slug: "v110", Title: "v1.1.0", Type: ChangelogTypeAdded, Body: "### Added\n\n* Search (old wording)\n"},
+			{Slug: "v100", Title: "v1.0.0", Type: ChangelogTypeRemoved, Body: "### Removed\n\n* Legacy endpoints\n"},
+		}
+
+		requests := []string{}
+		client := newChangelogSyncClient(t, existing, &requests)
+
+		result, err := SyncKeepAChangelog(context.Background(), client.Changelogs, releases, KeepAChangelogSyncOptions{
+			TitleFormat: "v%s",
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"v1-2-0"}, result.Created)
+		assert.Equal(t, []string{"v110"}, result.Updated)
+		assert.Equal(t, []string{"v100"}, result.Skipped)
+		assert.Equal(t, []string{
+			"GET /api/v1/changelogs",
+			"POST /api/v1/changelogs",
+			"PUT /api/v1/changelogs/v110",
+		}, requests)
+	})
+
+	t.Run("keeps versions with colliding slugs apart", func(t *testing.T) {
+		releases, err := ParseKeepAChangelog(strings.NewReader(`## [10.0] - 2021-10-01
+### Added
+- Webhooks
+
+## [1.0.0] - 2021-08-01
+### Added
+- Search
+`))
+		assert.Nil(t, err)
+
+		// ReadMe dropped the dots of both titles when the entries were created
+		existing := []*Changelog{
+			{Slug: "100", Title: "1.0.0", Type: ChangelogTypeAdded, Body: "### Added\n\n* Search\n"},
+		}
+
+		requests := []string{}
+		client := newChangelogSyncClient(t, existing, &requests)
+
+		result, err := SyncKeepAChangelog(context.Background(), client.Changelogs, releases, KeepAChangelogSyncOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"10-0"}, result.Created)
+		assert.Empty(t, result.Updated)
+		assert.Equal(t, []string{"100"}, result.Skipped)
+		assert.Equal(t, []string{"GET /api/v1/changelogs", "POST /api/v1/changelogs"}, requests)
+	})
+
+	t.Run("reports titles on dry runs", func(t *testing.T) {
+		releases, err := ParseKeepAChangelog(strings.NewReader("## [2.0.0] - 2021-10-01\n### Added\n- Feeds\n"))
+		assert.Nil(t, err)
+
+		requests := []string{}
+		client := newChangelogSyncClient(t, []*Changelog{}, &requests)
+
+		result, err := SyncKeepAChangelog(context.Background(), client.Changelogs, releases, KeepAChangelogSyncOptions{DryRun: true})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"2.0.0"}, result.Created)
+		assert.Equal(t, []string{"GET /api/v1/changelogs"}, requests)
+	})
+}
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "v120", slugify("v1.2.0"))
+	assert.Equal(t, "getting-started-with-readme", slugify(" Getting Started  with ReadMe! "))
+}
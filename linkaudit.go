@@ -0,0 +1,369 @@
+package readme
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/brandonc/go-readme/markdown"
+)
+
+const (
+	// LinkSourceDoc is a link found in the body of a doc
+	LinkSourceDoc = "doc"
+
+	// LinkSourceCustomPage is a link found in the body of a custom page
+	LinkSourceCustomPage = "custompage"
+
+	// LinkSourceChangelog is a link found in the body of a changelog
+	LinkSourceChangelog = "changelog"
+)
+
+const (
+	// LinkStatusBroken is a link to a page that doesn't exist
+	LinkStatusBroken = "broken"
+
+	// LinkStatusRedirected is a link to the previous slug of a renamed doc
+	LinkStatusRedirected = "redirected"
+)
+
+// LinkAuditOptions are the options available when auditing links
+type LinkAuditOptions struct {
+	// BaseURL is the public URL of the project's hub, ex. "https://docs.example.com". Absolute links
+	// to it are resolved like internal links.
+	BaseURL string
+
+	// CheckExternal enables requests to external links to check that they respond successfully
+	CheckExternal bool
+
+	// HttpClient is used for external checks. Defaults to a client with a 10 second timeout.
+	HttpClient *http.Client
+
+	// ApiDefinitions are the OpenAPI or Swagger definitions of the version's API specifications.
+	// Links to their operations, ex. "/reference/getPets", resolve even without a doc page. The
+	// API doesn't return the definition of an uploaded specification, so they are given here.
+	ApiDefinitions []json.RawMessage
+}
+
+// LinkProblem is a broken or redirected link
+type LinkProblem struct {
+	// Kind is the kind of page the link was found on, ex. LinkSourceDoc
+	Kind string
+
+	// Slug is the slug of the page the link was found on
+	Slug string
+
+	// Line is the 1-based line of the page body the link was found on
+	Line int
+
+	// URL is the destination of the link, exactly as written
+	URL  string
+	Text string
+
+	// Status is LinkStatusBroken or LinkStatusRedirected
+	Status string
+
+	// Target is the current slug of the doc a redirected link points to
+	Target string
+
+	// Message describes the problem
+	Message string
+}
+
+func (p *LinkProblem) String() string {
+	return fmt.Sprintf("%s %s line %d: %s: %s", p.Kind, p.Slug, p.Line, p.URL, p.Message)
+}
+
+// LinkAudit is the result of auditing the links of a version
+type LinkAudit struct {
+	Version string
+
+	// Checked is the number of links that were checked
+	Checked int
+
+	Problems []*LinkProblem
+}
+
+// linkIndex holds the slugs internal links are resolved against
+type linkIndex struct {
+	docs        map[string]bool
+	references  map[string]bool
+	pages       map[string]bool
+	changelogs  map[string]bool
+	renamedDocs map[string]string
+}
+
+// linkChecker resolves links, caching the results of external checks
+type linkChecker struct {
+	index    *linkIndex
+	opt      LinkAuditOptions
+	base     *url.URL
+	external map[string]string
+}
+
+var (
+	linkShorthand = regexp.MustCompile(`^(doc|ref|page|blog|changelog):([^#?\s]+)`)
+	linkPath      = regexp.MustCompile(`^(?:/v[^/]+)?/(docs|reference|page|changelog|blog)/([^/#?]+)/?(?:[#?].*)?$`)
+)
+
+// AuditLinks crawls the docs of a version (semver, ex. "1.0"), the custom pages and the changelogs of
+// the project, and reports internal links that point to pages that don't exist or to renamed docs.
+// Internal links are written as "doc:slug", "ref:slug", "page:slug" and "changelog:slug", or as paths
+// such as "/docs/slug" and "/reference/slug". External links are only checked when enabled.
+func (c *Client) AuditLinks(ctx context.Context, version string, opt LinkAuditOptions) (*LinkAudit, error) {
+	if opt.HttpClient == nil {
+		opt.HttpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	content, err := c.fetchVersionContent(ctx, version)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch version %s: %w", version, err)
+	}
+
+	pages, err := listAllCustomPages(ctx, c.CustomPages)
+	if err != nil {
+		return nil, fmt.Errorf("could not list custom pages: %w", err)
+	}
+
+	changelogs, err := listAllChangelogs(ctx, c.Changelogs)
+	if err != nil {
+		return nil, fmt.Errorf("could not list changelogs: %w", err)
+	}
+
+	index := newLinkIndex(content, pages, changelogs)
+	for i, definition := range opt.ApiDefinitions {
+		if err := index.addOperations(definition); err != nil {
+			return nil, fmt.Errorf("could not read API definition %d: %w", i, err)
+		}
+	}
+
+	checker := &linkChecker{
+		index:    index,
+		opt:      opt,
+		external: make(map[string]string),
+	}
+
+	if opt.BaseURL != "" {
+		if checker.base, err = url.Parse(opt.BaseURL); err != nil {
+			return nil, fmt.Errorf("could not parse base URL: %w", err)
+		}
+	}
+
+	result := &LinkAudit{Version: version, Problems: []*LinkProblem{}}
+	audit := func(kind string, slug string, body string) {
+		for _, link := range markdown.ExtractLinks(body) {
+			result.Checked++
+			if problem := checker.check(ctx, link); problem != nil {
+				problem.Kind = kind
+				problem.Slug = slug
+				result.Problems = append(result.Problems, problem)
+			}
+		}
+	}
+
+	for _, slug := range sortedDocSlugs(content.docs) {
+		audit(LinkSourceDoc, slug, content.docs[slug].Body)
+	}
+
+	for _, page := range pages {
+		if page.HtmlMode {
+			audit(LinkSourceCustomPage, page.Slug, page.Html)
+		} else {
+			audit(LinkSourceCustomPage, page.Slug, page.Body)
+		}
+	}
+
+	for _, changelog := range changelogs {
+		audit(LinkSourceChangelog, changelog.Slug, changelog.Body)
+	}
+
+	return result, nil
+}
+
+func newLinkIndex(content *versionContent, pages []*CustomPage, changelogs []*Changelog) *linkIndex {
+	index := &linkIndex{
+		docs:        make(map[string]bool),
+		references:  make(map[string]bool),
+		pages:       make(map[string]bool),
+		changelogs:  make(map[string]bool),
+		renamedDocs: make(map[string]string),
+	}
+
+	references := ReferenceCategoryIDs(content.categories)
+
+	for slug, doc := range content.docs {
+		if IsReferenceDoc(doc, references) {
+			index.references[slug] = true
+		} else {
+			index.docs[slug] = true
+		}
+
+		if doc.PreviousSlug != "" && doc.PreviousSlug != slug {
+			index.renamedDocs[doc.PreviousSlug] = slug
+		}
+	}
+
+	for _, page := range pages {
+		index.pages[page.Slug] = true
+	}
+
+	for _, changelog := range changelogs {
+		index.changelogs[changelog.Slug] = true
+	}
+
+	return index
+}
+
+// addOperations indexes the operations of an API definition as reference pages. ReadMe links to an
+// operation by its operation ID or by the slug of its summary.
+func (index *linkIndex) addOperations(definition json.RawMessage) error {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(definition, &spec); err != nil {
+		return err
+	}
+
+	for _, path := range spec.Paths {
+		for method, raw := range path {
+			switch method {
+			case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+			default:
+				// Path level parameters, servers and extensions aren't operations
+				continue
+			}
+
+			var operation struct {
+				OperationID string `json:"operationId"`
+				Summary     string `json:"summary"`
+			}
+			if err := json.Unmarshal(raw, &operation); err != nil {
+				return err
+			}
+
+			for _, slug := range []string{operation.OperationID, slugify(operation.OperationID), slugify(operation.Summary)} {
+				if slug != "" {
+					index.references[slug] = true
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// check resolves a link, returning nil when the link is fine or can't be checked
+func (l *linkChecker) check(ctx context.Context, link *markdown.Link) *LinkProblem {
+	target := link.URL
+
+	if l.base != nil {
+		if u, err := url.Parse(target); err == nil && u.Host != "" && strings.EqualFold(u.Host, l.base.Host) {
+			target = u.Path
+			if u.Fragment != "" {
+				target += "#" + u.Fragment
+			}
+		}
+	}
+
+	if match := linkShorthand.FindStringSubmatch(target); match != nil {
+		return l.internal(link, match[1], match[2])
+	}
+
+	if match := linkPath.FindStringSubmatch(target); match != nil {
+		section := match[1]
+		if section == "docs" {
+			section = "doc"
+		}
+		return l.internal(link, section, match[2])
+	}
+
+	if l.opt.CheckExternal && (strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://")) {
+		if message := l.checkExternal(ctx, target); message != "" {
+			return &LinkProblem{Line: link.Line, URL: link.URL, Text: link.Text, Status: LinkStatusBroken, Message: message}
+		}
+	}
+
+	return nil
+}
+
+// internal resolves a link to a slug in a section of the hub
+func (l *linkChecker) internal(link *markdown.Link, section string, slug string) *LinkProblem {
+	problem := &LinkProblem{Line: link.Line, URL: link.URL, Text: link.Text, Status: LinkStatusBroken}
+
+	switch section {
+	case "doc", "ref", "reference":
+		slugs, name := l.index.docs, "doc"
+		if section != "doc" {
+			slugs, name = l.index.references, "reference page"
+		}
+
+		if slugs[slug] {
+			return nil
+		}
+
+		if renamed, ok := l.index.renamedDocs[slug]; ok {
+			problem.Status = LinkStatusRedirected
+			problem.Target = renamed
+			problem.Message = fmt.Sprintf("%s was renamed to %s", slug, renamed)
+			return problem
+		}
+
+		problem.Message = fmt.Sprintf("%s %s does not exist", name, slug)
+
+	case "page":
+		if l.index.pages[slug] {
+			return nil
+		}
+		problem.Message = fmt.Sprintf("custom page %s does not exist", slug)
+
+	default:
+		if l.index.changelogs[slug] {
+			return nil
+		}
+		problem.Message = fmt.Sprintf("changelog %s does not exist", slug)
+	}
+
+	return problem
+}
+
+// checkExternal requests an external link, returning a description of the failure or an empty string
+func (l *linkChecker) checkExternal(ctx context.Context, target string) string {
+	if message, ok := l.external[target]; ok {
+		return message
+	}
+
+	message := ""
+	status, err := l.request(ctx, http.MethodHead, target)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented) {
+		status, err = l.request(ctx, http.MethodGet, target)
+	}
+
+	if err != nil {
+		message = fmt.Sprintf("request failed: %v", err)
+	} else if status >= 400 {
+		message = fmt.Sprintf("responded with %d %s", status, http.StatusText(status))
+	}
+
+	l.external[target] = message
+	return message
+}
+
+func (l *linkChecker) request(ctx context.Context, method string, target string) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	response, err := l.opt.HttpClient.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	response.Body.Close()
+
+	return response.StatusCode, nil
+}
@@ -0,0 +1,125 @@
+package readme
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_AuditLinks(t *testing.T) {
+	external := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer external.Close()
+
+	categories := []*Category{
+		{Slug: "documentation", ID: "c1"},
+		{Slug: "pets-api", ID: "c2", Reference: true},
+	}
+	categoryDocs := map[string][]*CategoryDoc{
+		"documentation": {{Slug: "getting-started", Children: []*CategoryDoc{{Slug: "authentication"}}}},
+		"pets-api":      {{Slug: "get-pets"}},
+	}
+	docs := map[string]*Doc{
+		"getting-started": {Slug: "getting-started", Category: "c1", Body: "See [auth](doc:auth) and [pets](ref:get-pets).\n\n" +
+			"[Missing](/docs/missing) [About](/v1.0/page/about) [Gone](" + external.URL + "/missing)"},
+		"authentication": {Slug: "authentication", Category: "c1", PreviousSlug: "auth", Body: "Back to the [start](https://docs.example.com/docs/getting-started#top)."},
+		"get-pets":       {Slug: "get-pets", Category: "c2", Body: "[Create](/reference/create-pet) [Up](" + external.URL + "/ok)"},
+	}
+
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, DefaultBasePath+"/")
+
+		switch {
+		case path == "categories":
+			writePage(w, len(categories), categories)
+		case strings.HasPrefix(path, "categories/"):
+			writeJSON(w, categoryDocs[strings.TrimSuffix(strings.TrimPrefix(path, "categories/"), "/docs")])
+		case strings.HasPrefix(path, "docs/"):
+			writeJSON(w, docs[strings.TrimPrefix(path, "docs/")])
+		case path == "custompages":
+			writePage(w, 1, []*CustomPage{{Slug: "about", HtmlMode: true, Html: "<a href=\"changelog:v2\">What's new</a>"}})
+		case path == "changelogs":
+			writePage(w, 1, []*Changelog{{Slug: "v1", Body: "Read the [guide](doc:getting-started)"}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	t.Run("reports broken and redirected internal links", func(t *testing.T) {
+		result, err := client.AuditLinks(context.Background(), "1.0", LinkAuditOptions{BaseURL: "https://docs.example.com"})
+		assert.Nil(t, err)
+
+		assert.Equal(t, "1.0", result.Version)
+		assert.Equal(t, 10, result.Checked)
+		assert.Equal(t, []*LinkProblem{
+			{Kind: LinkSourceCustomPage, Slug: "about", Line: 1, URL: "changelog:v2", Status: LinkStatusBroken, Message: "changelog v2 does not exist"},
+			{Kind: LinkSourceDoc, Slug: "get-pets", Line: 1, URL: "/reference/create-pet", Text: "Create", Status: LinkStatusBroken, Message: "reference page create-pet does not exist"},
+			{Kind: LinkSourceDoc, Slug: "getting-started", Line: 1, URL: "doc:auth", Text: "auth", Status: LinkStatusRedirected, Target: "authentication", Message: "auth was renamed to authentication"},
+			{Kind: LinkSourceDoc, Slug: "getting-started", Line: 3, URL: "/docs/missing", Text: "Missing", Status: LinkStatusBroken, Message: "doc missing does not exist"},
+		}, sortLinkProblems(result.Problems))
+	})
+
+	t.Run("resolves links to the operations of API definitions", func(t *testing.T) {
+		definition := json.RawMessage(`{"openapi":"3.0.0","paths":{"/pets":{
+			"parameters":[{"name":"limit","in":"query"}],
+			"post":{"operationId":"createPet","summary":"Create a pet"},
+			"get":{"operationId":"listPets"}
+		}}}`)
+		defer func(body string) { docs["get-pets"].Body = body }(docs["get-pets"].Body)
+
+		for _, link := range []string{"/reference/create-a-pet", "/reference/createPet", "/reference/createpet", "ref:listPets"} {
+			docs["get-pets"].Body = "[Create](" + link + ")"
+
+			result, err := client.AuditLinks(context.Background(), "1.0", LinkAuditOptions{ApiDefinitions: []json.RawMessage{definition}})
+			assert.Nil(t, err)
+			for _, problem := range result.Problems {
+				assert.NotEqual(t, "get-pets", problem.Slug, link)
+			}
+		}
+
+		docs["get-pets"].Body = "[Delete](/reference/delete-pet)"
+		result, err := client.AuditLinks(context.Background(), "1.0", LinkAuditOptions{ApiDefinitions: []json.RawMessage{definition}})
+		assert.Nil(t, err)
+		assert.Contains(t, result.Problems, &LinkProblem{Kind: LinkSourceDoc, Slug: "get-pets", Line: 1, URL: "/reference/delete-pet", Text: "Delete", Status: LinkStatusBroken, Message: "reference page delete-pet does not exist"})
+
+		_, err = client.AuditLinks(context.Background(), "1.0", LinkAuditOptions{ApiDefinitions: []json.RawMessage{json.RawMessage(`[]`)}})
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "could not read API definition 0")
+		}
+	})
+
+	t.Run("checks external links", func(t *testing.T) {
+		result, err := client.AuditLinks(context.Background(), "1.0", LinkAuditOptions{CheckExternal: true, HttpClient: external.Client()})
+		assert.Nil(t, err)
+
+		var broken []string
+		for _, problem := range result.Problems {
+			if strings.HasPrefix(problem.URL, external.URL) {
+				broken = append(broken, problem.String())
+			}
+		}
+
+		assert.Equal(t, []string{"doc getting-started line 3: " + external.URL + "/missing: responded with 404 Not Found"}, broken)
+	})
+}
+
+// sortLinkProblems puts custom page problems first so the expected order doesn't depend on crawling
+func sortLinkProblems(problems []*LinkProblem) []*LinkProblem {
+	result := []*LinkProblem{}
+	for _, kind := range []string{LinkSourceCustomPage, LinkSourceDoc, LinkSourceChangelog} {
+		for _, problem := range problems {
+			if problem.Kind == kind {
+				result = append(result, problem)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,264 @@
+// Package markdown parses the ReadMe-flavored Markdown found in the bodies of docs, changelogs and
+// custom pages into a typed AST, and serializes it back without disturbing untouched content.
+//
+// ReadMe bodies mix three syntaxes: plain Markdown, legacy "magic blocks" of JSON wrapped in
+// [block:name] ... [/block], and newer MDX-style components such as <Callout>. Nodes that can be
+// written in more than one syntax record which one they were parsed from, so changing a node's
+// Syntax converts it.
+package markdown
+
+// Syntax is the source syntax of a node
+type Syntax int
+
+const (
+	// SyntaxMarkdown is plain (GitHub-flavored) Markdown
+	SyntaxMarkdown Syntax = iota
+
+	// SyntaxMagicBlock is a legacy [block:name] JSON magic block
+	SyntaxMagicBlock
+
+	// SyntaxMDX is an MDX-style component, ex. <Callout>
+	SyntaxMDX
+)
+
+// Callout types
+const (
+	CalloutInfo    = "info"
+	CalloutSuccess = "success"
+	CalloutWarning = "warning"
+	CalloutDanger  = "danger"
+)
+
+// Table column alignments
+const (
+	AlignNone   = ""
+	AlignLeft   = "left"
+	AlignCenter = "center"
+	AlignRight  = "right"
+)
+
+// Node is a block of a ReadMe-flavored Markdown document
+type Node interface {
+	meta() *nodeMeta
+}
+
+// nodeMeta records where a parsed node came from, so unmodified nodes serialize to their exact source
+type nodeMeta struct {
+	parsed bool
+	first  bool
+
+	// line is the 1-based line number the node starts on
+	line int
+
+	// raw is the source text of the node
+	raw string
+
+	// lead is the whitespace between the previous node and this one
+	lead string
+
+	// canonical is the canonical serialization of the node at parse time
+	canonical string
+}
+
+func (m *nodeMeta) meta() *nodeMeta {
+	return m
+}
+
+// Line returns the 1-based line number of the source the node was parsed from, or 0 for nodes
+// that were not parsed
+func Line(node Node) int {
+	return node.meta().line
+}
+
+// Document is the root node of a parsed body
+type Document struct {
+	nodeMeta
+	Children []Node
+
+	// trailing is the text after the last node
+	trailing string
+}
+
+// Heading is an ATX heading, or a legacy api-header magic block
+type Heading struct {
+	nodeMeta
+	Syntax Syntax
+	Level  int
+	Text   string
+}
+
+// Paragraph is a run of text lines
+type Paragraph struct {
+	nodeMeta
+	Text string
+}
+
+// ThematicBreak is a horizontal rule
+type ThematicBreak struct {
+	nodeMeta
+}
+
+// Blockquote is a quote that is not a callout. Text excludes the leading "> " markers.
+type Blockquote struct {
+	nodeMeta
+	Text string
+}
+
+// List is an ordered or unordered list
+type List struct {
+	nodeMeta
+	Ordered bool
+
+	// Start is the number of the first item of an ordered list
+	Start int
+
+	// Marker is the bullet of an unordered list ('-', '*' or '+') or the delimiter following the
+	// number of an ordered list ('.' or ')')
+	Marker byte
+
+	// Loose lists separate their items with blank lines
+	Loose bool
+
+	Items []*ListItem
+}
+
+// ListItem is a single list item. Content is the Markdown of the item with its indentation removed.
+type ListItem struct {
+	Content string
+}
+
+// CodeBlock is a single fenced code block
+type CodeBlock struct {
+	nodeMeta
+	Language string
+
+	// Name is the tab title of the code block, given after the language of the fence info string
+	Name string
+	Code string
+}
+
+// CodeTabs is a group of code blocks shown as tabs. In Markdown, code tabs are fenced code blocks
+// with no blank lines between them.
+type CodeTabs struct {
+	nodeMeta
+	Syntax Syntax
+	Tabs   []*CodeBlock
+}
+
+// Callout is a highlighted note. In Markdown, callouts are blockquotes that begin with an emoji.
+type Callout struct {
+	nodeMeta
+	Syntax Syntax
+
+	// Type is the callout type, ex. CalloutInfo
+	Type  string
+	Icon  string
+	Title string
+	Body  string
+}
+
+// Table is a table with a header row. Align holds the alignment of each column.
+type Table struct {
+	nodeMeta
+	Syntax Syntax
+	Header []string
+	Align  []string
+	Rows   [][]string
+}
+
+// Image is a single image on its own line
+type Image struct {
+	nodeMeta
+	Syntax  Syntax
+	Src     string
+	Alt     string
+	Title   string
+	Caption string
+	Width   int
+	Height  int
+}
+
+// Embed is embedded content from another site, such as a video or a gist
+type Embed struct {
+	nodeMeta
+	Syntax   Syntax
+	URL      string
+	Title    string
+	Provider string
+	HTML     string
+}
+
+// Recipe links to a ReadMe recipe (tutorial). Recipes can't be written in plain Markdown.
+type Recipe struct {
+	nodeMeta
+	Syntax Syntax
+	Slug   string
+	Title  string
+	Emoji  string
+}
+
+// HTML is a block of raw HTML
+type HTML struct {
+	nodeMeta
+	Syntax Syntax
+	HTML   string
+}
+
+// RawBlock is a magic block that isn't otherwise understood. Content is the text between the
+// [block:name] and [/block] lines.
+type RawBlock struct {
+	nodeMeta
+	Name    string
+	Content string
+}
+
+// Visitor visits the nodes of a document. If Visit returns a non-nil visitor w, the children of the
+// node are visited with w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses the node and its children in depth-first order
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Document:
+		for _, child := range n.Children {
+			Walk(v, child)
+		}
+	case *CodeTabs:
+		for _, tab := range n.Tabs {
+			Walk(v, tab)
+		}
+	}
+
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses the node and its children in depth-first order, calling f for each node. If f
+// returns false, the children of the node are skipped. Nodes may be modified in place.
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+// Rewrite replaces each top level node of the document with the nodes returned by f. Returning the
+// node itself keeps it, returning nil removes it and returning several nodes inserts new ones.
+func Rewrite(doc *Document, f func(Node) []Node) {
+	children := make([]Node, 0, len(doc.Children))
+	for _, child := range doc.Children {
+		children = append(children, f(child)...)
+	}
+	doc.Children = children
+}
@@ -0,0 +1,61 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspect(t *testing.T) {
+	t.Run("visits code tabs", func(t *testing.T) {
+		doc := Parse("```js\nvar a\n```\n```py\na = 1\n```\n\n```go\nvar a int\n```\n")
+
+		languages := []string{}
+		Inspect(doc, func(node Node) bool {
+			if block, ok := node.(*CodeBlock); ok {
+				languages = append(languages, block.Language)
+				block.Language = strings.ToUpper(block.Language)
+			}
+			return true
+		})
+
+		assert.Equal(t, []string{"js", "py", "go"}, languages)
+		assert.Equal(t, "```JS\nvar a\n```\n```PY\na = 1\n```\n\n```GO\nvar a int\n```\n", doc.String())
+	})
+
+	t.Run("skips children when returning false", func(t *testing.T) {
+		doc := Parse("```js\nvar a\n```\n```py\na = 1\n```\n")
+
+		visited := 0
+		Inspect(doc, func(node Node) bool {
+			if node != nil {
+				visited++
+			}
+			_, tabs := node.(*CodeTabs)
+			return !tabs
+		})
+
+		assert.Equal(t, 2, visited)
+	})
+}
+
+func TestRewrite(t *testing.T) {
+	t.Run("removes, replaces and inserts nodes", func(t *testing.T) {
+		doc := Parse("# Title\n\nRemove me\n\nKeep me\n")
+
+		Rewrite(doc, func(node Node) []Node {
+			switch n := node.(type) {
+			case *Heading:
+				return []Node{n, &Callout{Type: CalloutInfo, Title: "Beta"}}
+			case *Paragraph:
+				if n.Text == "Remove me" {
+					return nil
+				}
+			}
+			return []Node{node}
+		})
+
+		assert.Equal(t, "# Title\n\n> 📘 Beta\n\nKeep me\n", doc.String())
+	})
+}
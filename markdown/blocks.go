@@ -0,0 +1,238 @@
+package markdown
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type magicAPIHeader struct {
+	Title string `json:"title"`
+	Level int    `json:"level,omitempty"`
+}
+
+type magicCode struct {
+	Codes []*magicCodeTab `json:"codes"`
+}
+
+type magicCodeTab struct {
+	Code     string `json:"code"`
+	Language string `json:"language"`
+	Name     string `json:"name,omitempty"`
+}
+
+type magicCallout struct {
+	Type  string `json:"type"`
+	Icon  string `json:"icon,omitempty"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type magicParameters struct {
+	Data  map[string]string `json:"data"`
+	Cols  int               `json:"cols"`
+	Rows  int               `json:"rows"`
+	Align []string          `json:"align,omitempty"`
+}
+
+type magicImages struct {
+	Images []*magicImage `json:"images"`
+}
+
+type magicImage struct {
+	// Image is [url, name, width, height, color]
+	Image   []interface{} `json:"image"`
+	Caption string        `json:"caption,omitempty"`
+}
+
+type magicEmbed struct {
+	URL      string `json:"url"`
+	Title    string `json:"title,omitempty"`
+	Provider string `json:"provider,omitempty"`
+	HTML     string `json:"html,omitempty"`
+}
+
+type magicTutorialTile struct {
+	Emoji string `json:"emoji,omitempty"`
+	Slug  string `json:"slug"`
+	Title string `json:"title"`
+}
+
+type magicHTML struct {
+	HTML string `json:"html"`
+}
+
+// decodeMagicBlock decodes the JSON content of a magic block into a typed node, falling back to a
+// RawBlock for unknown block names or content that can't be decoded
+func decodeMagicBlock(name string, content string) Node {
+	raw := &RawBlock{Name: name, Content: content}
+	data := []byte(content)
+
+	switch name {
+	case "api-header":
+		v := magicAPIHeader{}
+		if json.Unmarshal(data, &v) != nil {
+			return raw
+		}
+		if v.Level == 0 {
+			v.Level = 1
+		}
+		return &Heading{Syntax: SyntaxMagicBlock, Level: v.Level, Text: v.Title}
+
+	case "code":
+		v := magicCode{}
+		if json.Unmarshal(data, &v) != nil {
+			return raw
+		}
+		tabs := &CodeTabs{Syntax: SyntaxMagicBlock, Tabs: []*CodeBlock{}}
+		for _, code := range v.Codes {
+			tabs.Tabs = append(tabs.Tabs, &CodeBlock{Code: code.Code, Language: code.Language, Name: code.Name})
+		}
+		return tabs
+
+	case "callout":
+		v := magicCallout{}
+		if json.Unmarshal(data, &v) != nil {
+			return raw
+		}
+		return &Callout{Syntax: SyntaxMagicBlock, Type: v.Type, Icon: v.Icon, Title: v.Title, Body: v.Body}
+
+	case "parameters":
+		v := magicParameters{}
+		// every column and row has at least a header or cell in the data, which bounds the size of
+		// the table by the size of the document
+		if json.Unmarshal(data, &v) != nil || v.Cols < 0 || v.Rows < 0 || v.Cols > len(v.Data) || v.Rows > len(v.Data) {
+			return raw
+		}
+		table := &Table{Syntax: SyntaxMagicBlock, Header: make([]string, v.Cols), Align: make([]string, v.Cols), Rows: [][]string{}}
+		for col := 0; col < v.Cols; col++ {
+			table.Header[col] = v.Data["h-"+strconv.Itoa(col)]
+			if col < len(v.Align) {
+				table.Align[col] = v.Align[col]
+			}
+		}
+		for row := 0; row < v.Rows; row++ {
+			cells := make([]string, v.Cols)
+			for col := 0; col < v.Cols; col++ {
+				cells[col] = v.Data[fmt.Sprintf("%d-%d", row, col)]
+			}
+			table.Rows = append(table.Rows, cells)
+		}
+		return table
+
+	case "image":
+		v := magicImages{}
+		if json.Unmarshal(data, &v) != nil || len(v.Images) != 1 || len(v.Images[0].Image) == 0 {
+			return raw
+		}
+		image := &Image{Syntax: SyntaxMagicBlock, Caption: v.Images[0].Caption}
+		fields := v.Images[0].Image
+		image.Src, _ = fields[0].(string)
+		if len(fields) > 1 {
+			image.Alt, _ = fields[1].(string)
+		}
+		if len(fields) > 3 {
+			width, _ := fields[2].(float64)
+			height, _ := fields[3].(float64)
+			image.Width, image.Height = int(width), int(height)
+		}
+		return image
+
+	case "embed":
+		v := magicEmbed{}
+		if json.Unmarshal(data, &v) != nil {
+			return raw
+		}
+		return &Embed{Syntax: SyntaxMagicBlock, URL: v.URL, Title: v.Title, Provider: v.Provider, HTML: v.HTML}
+
+	case "tutorial-tile":
+		v := magicTutorialTile{}
+		if json.Unmarshal(data, &v) != nil {
+			return raw
+		}
+		return &Recipe{Syntax: SyntaxMagicBlock, Slug: v.Slug, Title: v.Title, Emoji: v.Emoji}
+
+	case "html":
+		v := magicHTML{}
+		if json.Unmarshal(data, &v) != nil {
+			return raw
+		}
+		return &HTML{Syntax: SyntaxMagicBlock, HTML: v.HTML}
+	}
+
+	return raw
+}
+
+// encodeMagicBlock renders a magic block the way the ReadMe editor does: indented JSON without
+// HTML escaping
+func encodeMagicBlock(name string, v interface{}) string {
+	buffer := new(bytes.Buffer)
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	// the values are all plain structs, maps and strings so encoding can't fail
+	encoder.Encode(v)
+
+	return "[block:" + name + "]\n" + strings.TrimSuffix(buffer.String(), "\n") + "\n[/block]"
+}
+
+// magicBlockValue returns the name and JSON value of the magic block representing the node
+func magicBlockValue(node Node) (string, interface{}) {
+	switch n := node.(type) {
+	case *Heading:
+		v := magicAPIHeader{Title: n.Text}
+		if n.Level > 1 {
+			v.Level = n.Level
+		}
+		return "api-header", v
+
+	case *CodeTabs:
+		v := magicCode{Codes: []*magicCodeTab{}}
+		for _, tab := range n.Tabs {
+			v.Codes = append(v.Codes, &magicCodeTab{Code: tab.Code, Language: tab.Language, Name: tab.Name})
+		}
+		return "code", v
+
+	case *Callout:
+		return "callout", magicCallout{Type: n.Type, Icon: n.Icon, Title: n.Title, Body: n.Body}
+
+	case *Table:
+		v := magicParameters{Data: make(map[string]string), Cols: len(n.Header), Rows: len(n.Rows)}
+		for col, cell := range n.Header {
+			v.Data["h-"+strconv.Itoa(col)] = cell
+		}
+		for row, cells := range n.Rows {
+			for col, cell := range cells {
+				v.Data[fmt.Sprintf("%d-%d", row, col)] = cell
+			}
+		}
+		for _, align := range n.Align {
+			if align != AlignNone {
+				v.Align = n.Align
+				break
+			}
+		}
+		return "parameters", v
+
+	case *Image:
+		fields := []interface{}{n.Src, n.Alt}
+		if n.Width > 0 || n.Height > 0 {
+			fields = append(fields, n.Width, n.Height)
+		}
+		return "image", magicImages{Images: []*magicImage{{Image: fields, Caption: n.Caption}}}
+
+	case *Embed:
+		return "embed", magicEmbed{URL: n.URL, Title: n.Title, Provider: n.Provider, HTML: n.HTML}
+
+	case *Recipe:
+		return "tutorial-tile", magicTutorialTile{Emoji: n.Emoji, Slug: n.Slug, Title: n.Title}
+
+	case *HTML:
+		return "html", magicHTML{HTML: n.HTML}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,356 @@
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ConversionIssue describes a node that could not be converted losslessly
+type ConversionIssue struct {
+	// Node is the node as it was before conversion
+	Node Node
+
+	// Line is the line of the source the node was parsed from, or 0
+	Line int
+
+	Message string
+}
+
+func (i *ConversionIssue) String() string {
+	if i.Line > 0 {
+		return fmt.Sprintf("line %d: %s", i.Line, i.Message)
+	}
+	return i.Message
+}
+
+// ConversionReport lists the issues found while converting a document
+type ConversionReport struct {
+	Issues []*ConversionIssue
+}
+
+// Lossless reports whether the conversion kept all information
+func (r *ConversionReport) Lossless() bool {
+	return len(r.Issues) == 0
+}
+
+func (r *ConversionReport) add(node Node, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, &ConversionIssue{
+		Node:    node,
+		Line:    Line(node),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// alertTypes maps callout types to GitHub alert types
+var alertTypes = map[string]string{
+	CalloutInfo:    "NOTE",
+	CalloutSuccess: "TIP",
+	CalloutWarning: "WARNING",
+	CalloutDanger:  "CAUTION",
+}
+
+// alertCallouts maps GitHub alert types to callout types
+var alertCallouts = map[string]string{
+	"NOTE":      CalloutInfo,
+	"IMPORTANT": CalloutInfo,
+	"TIP":       CalloutSuccess,
+	"WARNING":   CalloutWarning,
+	"CAUTION":   CalloutDanger,
+}
+
+var (
+	alertMarker   = regexp.MustCompile(`^\[!(\w+)\]\s*$`)
+	alertTitle    = regexp.MustCompile(`^\*\*(.+)\*\*\s*$`)
+	htmlImage     = regexp.MustCompile(`(?is)^<img\s[^>]*?/?>$`)
+	htmlTable     = regexp.MustCompile(`(?is)^<table>(.*)</table>$`)
+	htmlTableRow  = regexp.MustCompile(`(?is)<tr>(.*?)</tr>`)
+	htmlTableCell = regexp.MustCompile(`(?is)<(th|td)(\s+align="(\w+)")?>(.*?)</(?:th|td)>`)
+	htmlTableTags = regexp.MustCompile(`(?is)</?(?:thead|tbody)>`)
+	htmlLineBreak = regexp.MustCompile(`(?i)<br\s*/?>`)
+)
+
+// ConvertToGFM converts a ReadMe-flavored Markdown body to GitHub-flavored Markdown
+func ConvertToGFM(body string) (string, *ConversionReport) {
+	doc := Parse(body)
+	report := ToGFM(doc)
+	return doc.String(), report
+}
+
+// ConvertFromGFM converts a GitHub-flavored Markdown body to ReadMe-flavored Markdown
+func ConvertFromGFM(body string) (string, *ConversionReport) {
+	doc := Parse(body)
+	report := FromGFM(doc)
+	return doc.String(), report
+}
+
+// ToGFM converts the magic blocks and MDX components of the document to their closest GitHub-flavored
+// Markdown equivalents: code tabs become adjacent fenced code blocks, callouts become GitHub alerts,
+// parameter tables become HTML tables and images become <img> tags.
+func ToGFM(doc *Document) *ConversionReport {
+	report := &ConversionReport{Issues: []*ConversionIssue{}}
+
+	Rewrite(doc, func(node Node) []Node {
+		switch n := node.(type) {
+		case *Heading:
+			if n.Syntax != SyntaxMarkdown {
+				n.Syntax = SyntaxMarkdown
+			}
+
+		case *CodeTabs:
+			if n.Syntax != SyntaxMarkdown {
+				n.Syntax = SyntaxMarkdown
+			}
+
+		case *Callout:
+			return []Node{replaceNode(n, calloutToAlert(n, report))}
+
+		case *Table:
+			if n.Syntax != SyntaxMarkdown {
+				return []Node{replaceNode(n, &HTML{HTML: tableToHTML(n)})}
+			}
+
+		case *Image:
+			if n.Syntax != SyntaxMarkdown {
+				if n.Caption != "" {
+					report.add(n, "image caption %q was dropped", n.Caption)
+				}
+				return []Node{replaceNode(n, &HTML{HTML: imageToHTML(n)})}
+			}
+
+		case *Embed:
+			if n.HTML != "" || n.Provider != "" || n.Syntax != SyntaxMarkdown {
+				report.add(n, "embed of %s was converted to a link", n.URL)
+			}
+			title := n.Title
+			if title == "" {
+				title = n.URL
+			}
+			return []Node{replaceNode(n, &Paragraph{Text: "[" + title + "](" + n.URL + ")"})}
+
+		case *Recipe:
+			report.add(n, "recipe %q was converted to a link", n.Slug)
+			title := strings.TrimSpace(n.Emoji + " " + n.Title)
+			return []Node{replaceNode(n, &Paragraph{Text: "[" + title + "](/recipes/" + n.Slug + ")"})}
+
+		case *HTML:
+			n.Syntax = SyntaxMarkdown
+
+		case *RawBlock:
+			report.add(n, "magic block %q is not supported and was kept as is", n.Name)
+		}
+
+		return []Node{node}
+	})
+
+	return report
+}
+
+// FromGFM converts GitHub-flavored Markdown constructs of the document to ReadMe magic blocks:
+// adjacent fenced code blocks become code blocks, GitHub alerts become callouts, pipe and simple
+// HTML tables become parameter tables and images become image blocks.
+func FromGFM(doc *Document) *ConversionReport {
+	report := &ConversionReport{Issues: []*ConversionIssue{}}
+
+	Rewrite(doc, func(node Node) []Node {
+		switch n := node.(type) {
+		case *CodeBlock:
+			return []Node{replaceNode(n, &CodeTabs{Syntax: SyntaxMagicBlock, Tabs: []*CodeBlock{n}})}
+
+		case *CodeTabs:
+			n.Syntax = SyntaxMagicBlock
+
+		case *Blockquote:
+			if callout := alertToCallout(n); callout != nil {
+				return []Node{replaceNode(n, callout)}
+			}
+
+		case *Callout:
+			n.Syntax = SyntaxMagicBlock
+
+		case *Table:
+			n.Syntax = SyntaxMagicBlock
+
+		case *Image:
+			if n.Title != "" {
+				report.add(n, "image title %q was dropped", n.Title)
+			}
+			n.Syntax = SyntaxMagicBlock
+
+		case *HTML:
+			trimmed := strings.TrimSpace(n.HTML)
+			switch {
+			case htmlImage.MatchString(trimmed):
+				return []Node{replaceNode(n, htmlToImage(trimmed))}
+			case strings.HasPrefix(strings.ToLower(trimmed), "<table"):
+				if table := htmlToTable(trimmed); table != nil {
+					return []Node{replaceNode(n, table)}
+				}
+				report.add(n, "HTML table is too complex for a parameters block and was kept as HTML")
+			}
+		}
+
+		return []Node{node}
+	})
+
+	return report
+}
+
+// replaceNode keeps the position of the old node in the source for the new node
+func replaceNode(old Node, new Node) Node {
+	from, to := old.meta(), new.meta()
+	to.lead = from.lead
+	to.first = from.first
+	to.line = from.line
+	return new
+}
+
+func calloutToAlert(n *Callout, report *ConversionReport) Node {
+	alert, ok := alertTypes[n.Type]
+	if !ok {
+		report.add(n, "callout type %q has no GitHub equivalent and was converted to a note", n.Type)
+		alert = "NOTE"
+	}
+
+	if n.Icon != "" && n.Icon != defaultCalloutIcons[n.Type] && calloutIcons[n.Icon] != n.Type {
+		report.add(n, "callout icon %s was dropped", n.Icon)
+	}
+
+	lines := []string{"[!" + alert + "]"}
+	if n.Title != "" {
+		lines = append(lines, "**"+n.Title+"**")
+	}
+	if n.Body != "" {
+		if n.Title != "" {
+			lines = append(lines, "")
+		}
+		lines = append(lines, n.Body)
+	}
+
+	return &Blockquote{Text: strings.Join(lines, "\n")}
+}
+
+func alertToCallout(n *Blockquote) *Callout {
+	lines := strings.Split(n.Text, "\n")
+	match := alertMarker.FindStringSubmatch(lines[0])
+	if match == nil {
+		return nil
+	}
+
+	calloutType, ok := alertCallouts[strings.ToUpper(match[1])]
+	if !ok {
+		return nil
+	}
+
+	callout := &Callout{Syntax: SyntaxMagicBlock, Type: calloutType}
+	lines = lines[1:]
+	if len(lines) > 0 {
+		if title := alertTitle.FindStringSubmatch(lines[0]); title != nil {
+			callout.Title = title[1]
+			lines = lines[1:]
+		}
+	}
+	callout.Body = trimBlankLines(lines)
+
+	return callout
+}
+
+func tableToHTML(n *Table) string {
+	cell := func(tag string, col int, text string) string {
+		align := ""
+		if col < len(n.Align) && n.Align[col] != AlignNone {
+			align = ` align="` + n.Align[col] + `"`
+		}
+		return "<" + tag + align + ">" + strings.ReplaceAll(text, "\n", "<br>") + "</" + tag + ">"
+	}
+
+	lines := []string{"<table>", "<thead>", "<tr>"}
+	for col, text := range n.Header {
+		lines = append(lines, cell("th", col, text))
+	}
+	lines = append(lines, "</tr>", "</thead>", "<tbody>")
+	for _, row := range n.Rows {
+		lines = append(lines, "<tr>")
+		for col, text := range row {
+			lines = append(lines, cell("td", col, text))
+		}
+		lines = append(lines, "</tr>")
+	}
+	lines = append(lines, "</tbody>", "</table>")
+
+	return strings.Join(lines, "\n")
+}
+
+// htmlToTable parses tables written the way tableToHTML writes them, returning nil for anything else
+func htmlToTable(source string) *Table {
+	match := htmlTable.FindStringSubmatch(source)
+	if match == nil {
+		return nil
+	}
+
+	content := htmlTableTags.ReplaceAllString(match[1], "")
+	if strings.Contains(strings.ToLower(content), "<table") {
+		return nil
+	}
+
+	table := &Table{Syntax: SyntaxMagicBlock, Rows: [][]string{}}
+	for i, row := range htmlTableRow.FindAllStringSubmatch(content, -1) {
+		cells := htmlTableCell.FindAllStringSubmatch(row[1], -1)
+		if len(cells) == 0 {
+			return nil
+		}
+
+		texts := make([]string, len(cells))
+		for col, cell := range cells {
+			texts[col] = htmlLineBreak.ReplaceAllString(strings.TrimSpace(cell[4]), "\n")
+			if i == 0 {
+				table.Align = append(table.Align, cell[3])
+			}
+		}
+
+		if i == 0 {
+			table.Header = texts
+		} else {
+			table.Rows = append(table.Rows, texts)
+		}
+	}
+
+	if table.Header == nil {
+		return nil
+	}
+
+	return table
+}
+
+func imageToHTML(n *Image) string {
+	attrs := []string{`src="` + html.EscapeString(n.Src) + `"`}
+	if n.Alt != "" {
+		attrs = append(attrs, `alt="`+html.EscapeString(n.Alt)+`"`)
+	}
+	if n.Title != "" {
+		attrs = append(attrs, `title="`+html.EscapeString(n.Title)+`"`)
+	}
+	if n.Width > 0 {
+		attrs = append(attrs, `width="`+strconv.Itoa(n.Width)+`"`)
+	}
+	if n.Height > 0 {
+		attrs = append(attrs, `height="`+strconv.Itoa(n.Height)+`"`)
+	}
+	return "<img " + strings.Join(attrs, " ") + " />"
+}
+
+func htmlToImage(tag string) *Image {
+	attrs := parseAttributes(tag)
+	width, _ := strconv.Atoi(attrs["width"])
+	height, _ := strconv.Atoi(attrs["height"])
+
+	return &Image{
+		Syntax: SyntaxMagicBlock,
+		Src:    html.UnescapeString(attrs["src"]),
+		Alt:    html.UnescapeString(attrs["alt"]),
+		Width:  width,
+		Height: height,
+	}
+}
@@ -0,0 +1,133 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertToGFM(t *testing.T) {
+	t.Run("converts magic blocks", func(t *testing.T) {
+		body, report := ConvertToGFM(`[block:api-header]
+{"title": "Errors"}
+[/block]
+
+[block:callout]
+{"type": "danger", "title": "Careful", "body": "This deletes data."}
+[/block]
+
+[block:code]
+{"codes": [{"code": "curl -X DELETE", "language": "shell", "name": "cURL"}, {"code": "client.Delete()", "language": "go"}]}
+[/block]
+
+[block:parameters]
+{"data": {"h-0": "Code", "h-1": "Meaning", "0-0": "404", "0-1": "Not\nfound"}, "cols": 2, "rows": 1, "align": ["left", "left"]}
+[/block]
+
+[block:image]
+{"images": [{"image": ["https://files.readme.io/a.png", "a.png", 100, 50], "caption": "A diagram"}]}
+[/block]
+`)
+
+		assert.Equal(t, "# Errors\n\n> [!CAUTION]\n> **Careful**\n>\n> This deletes data.\n\n```shell cURL\ncurl -X DELETE\n```\n```go\nclient.Delete()\n```\n\n"+
+			"<table>\n<thead>\n<tr>\n<th align=\"left\">Code</th>\n<th align=\"left\">Meaning</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td align=\"left\">404</td>\n<td align=\"left\">Not<br>found</td>\n</tr>\n</tbody>\n</table>\n\n"+
+			"<img src=\"https://files.readme.io/a.png\" alt=\"a.png\" width=\"100\" height=\"50\" />\n", body)
+
+		assert.False(t, report.Lossless())
+		assert.Len(t, report.Issues, 1)
+		assert.Equal(t, `line 17: image caption "A diagram" was dropped`, report.Issues[0].String())
+	})
+
+	t.Run("reports embeds, recipes and unknown blocks", func(t *testing.T) {
+		body, report := ConvertToGFM("<Recipe slug=\"setup\" title=\"Set up\" />\n\n<Embed url=\"https://youtu.be/x\" title=\"Demo\" />\n\n[block:widget]\n{}\n[/block]\n")
+
+		assert.Equal(t, "[Set up](/recipes/setup)\n\n[Demo](https://youtu.be/x)\n\n[block:widget]\n{}\n[/block]\n", body)
+		assert.Len(t, report.Issues, 3)
+	})
+
+	t.Run("leaves markdown untouched", func(t *testing.T) {
+		src := "# Title\n\n| a | b |\n|---|---|\n| 1 | 2 |\n\n![x](y.png)\n"
+
+		body, report := ConvertToGFM(src)
+		assert.Equal(t, src, body)
+		assert.True(t, report.Lossless())
+	})
+}
+
+func TestConvertFromGFM(t *testing.T) {
+	t.Run("converts gfm constructs to magic blocks", func(t *testing.T) {
+		body, report := ConvertFromGFM("> [!TIP]\n> **Pro tip**\n>\n> Use tabs.\n\n```go\nfmt.Println()\n```\n\n| a | b |\n|:-:|---|\n| 1 | 2 |\n\n<img src=\"x.png\" alt=\"X\" width=\"10\" />\n")
+
+		assert.Equal(t, `[block:callout]
+{
+  "type": "success",
+  "title": "Pro tip",
+  "body": "Use tabs."
+}
+[/block]
+
+[block:code]
+{
+  "codes": [
+    {
+      "code": "fmt.Println()",
+      "language": "go"
+    }
+  ]
+}
+[/block]
+
+[block:parameters]
+{
+  "data": {
+    "0-0": "1",
+    "0-1": "2",
+    "h-0": "a",
+    "h-1": "b"
+  },
+  "cols": 2,
+  "rows": 1,
+  "align": [
+    "center",
+    ""
+  ]
+}
+[/block]
+
+[block:image]
+{
+  "images": [
+    {
+      "image": [
+        "x.png",
+        "X",
+        10,
+        0
+      ]
+    }
+  ]
+}
+[/block]
+`, body)
+		assert.True(t, report.Lossless())
+	})
+
+	t.Run("round trips magic blocks through gfm", func(t *testing.T) {
+		doc := Parse("[block:parameters]\n{\"data\": {\"h-0\": \"Code\", \"0-0\": \"a\\nb\"}, \"cols\": 1, \"rows\": 1}\n[/block]\n\n[block:callout]\n{\"type\": \"info\", \"title\": \"\", \"body\": \"Note\"}\n[/block]\n")
+		original := map[int]Node{0: stripMeta(doc.Children[0]), 1: stripMeta(doc.Children[1])}
+
+		ToGFM(doc)
+		converted := Parse(doc.String())
+		FromGFM(converted)
+
+		assert.Equal(t, original[1], stripMeta(converted.Children[1]))
+		table := converted.Children[0].(*Table)
+		assert.Equal(t, []string{"Code"}, table.Header)
+		assert.Equal(t, [][]string{{"a\nb"}}, table.Rows)
+	})
+
+	t.Run("reports complex html tables", func(t *testing.T) {
+		_, report := ConvertFromGFM("<table class=\"wide\"><tr><td colspan=\"2\">x</td></tr></table>\n")
+		assert.Len(t, report.Issues, 1)
+	})
+}
@@ -0,0 +1,275 @@
+package markdown
+
+import (
+	"strconv"
+	"strings"
+)
+
+// String serializes the document back to ReadMe-flavored Markdown. Nodes that have not been
+// modified since parsing are written exactly as they appeared in the source, so parsing and
+// serializing an unmodified document returns the original text.
+func (d *Document) String() string {
+	result := strings.Builder{}
+
+	for i, child := range d.Children {
+		meta := child.meta()
+
+		switch {
+		case i == 0 && meta.first:
+			result.WriteString(meta.lead)
+		case i == 0:
+		case meta.lead != "" && !meta.first:
+			result.WriteString(meta.lead)
+		default:
+			result.WriteString("\n\n")
+		}
+
+		result.WriteString(Format(child))
+	}
+
+	if d.parsed {
+		result.WriteString(d.trailing)
+	} else if len(d.Children) > 0 {
+		result.WriteString("\n")
+	}
+
+	return result.String()
+}
+
+// Format serializes a single node. Unmodified parsed nodes are returned as they appeared in the
+// source, other nodes are written in the canonical form of their Syntax.
+func Format(node Node) string {
+	if doc, ok := node.(*Document); ok {
+		return doc.String()
+	}
+
+	canonical := format(node)
+	if meta := node.meta(); meta.parsed && meta.canonical == canonical {
+		return meta.raw
+	}
+
+	return canonical
+}
+
+// format returns the canonical serialization of the node
+func format(node Node) string {
+	switch n := node.(type) {
+	case *Heading:
+		if n.Syntax == SyntaxMagicBlock {
+			break
+		}
+		level := n.Level
+		if level < 1 {
+			level = 1
+		} else if level > 6 {
+			level = 6
+		}
+		return strings.TrimSpace(strings.Repeat("#", level) + " " + n.Text)
+
+	case *Paragraph:
+		return n.Text
+
+	case *ThematicBreak:
+		return "---"
+
+	case *Blockquote:
+		return quote(n.Text)
+
+	case *List:
+		return formatList(n)
+
+	case *CodeBlock:
+		return formatFence(n)
+
+	case *CodeTabs:
+		if n.Syntax == SyntaxMagicBlock {
+			break
+		}
+		fences := make([]string, len(n.Tabs))
+		for i, tab := range n.Tabs {
+			fences[i] = formatFence(tab)
+		}
+		return strings.Join(fences, "\n")
+
+	case *Callout:
+		return formatCallout(n)
+
+	case *Table:
+		if n.Syntax == SyntaxMagicBlock {
+			break
+		}
+		return formatTable(n)
+
+	case *Image:
+		return formatImage(n)
+
+	case *Embed:
+		switch n.Syntax {
+		case SyntaxMarkdown:
+			return "[" + n.Title + "](" + n.URL + ` "@embed")`
+		case SyntaxMDX:
+			return "<Embed" + formatAttributes("url", n.URL, "title", n.Title, "provider", n.Provider, "html", n.HTML) + " />"
+		}
+
+	case *Recipe:
+		if n.Syntax != SyntaxMagicBlock {
+			return "<Recipe" + formatAttributes("slug", n.Slug, "title", n.Title, "emoji", n.Emoji) + " />"
+		}
+
+	case *HTML:
+		if n.Syntax != SyntaxMagicBlock {
+			return n.HTML
+		}
+
+	case *RawBlock:
+		return "[block:" + n.Name + "]\n" + n.Content + "\n[/block]"
+	}
+
+	name, value := magicBlockValue(node)
+	return encodeMagicBlock(name, value)
+}
+
+// quote prefixes each line of the text with "> "
+func quote(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = ">"
+		} else {
+			lines[i] = "> " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatFence(block *CodeBlock) string {
+	fence := "```"
+	for strings.Contains(block.Code, fence) {
+		fence += "`"
+	}
+
+	info := strings.TrimSpace(block.Language + " " + block.Name)
+	return fence + info + "\n" + block.Code + "\n" + fence
+}
+
+func formatCallout(n *Callout) string {
+	icon := n.Icon
+	if icon == "" {
+		icon = defaultCalloutIcons[n.Type]
+	}
+
+	switch n.Syntax {
+	case SyntaxMarkdown:
+		first := strings.TrimSpace(icon + " " + n.Title)
+		if n.Body == "" {
+			return "> " + first
+		}
+		return "> " + first + "\n" + quote("\n"+n.Body)
+
+	case SyntaxMDX:
+		content := n.Body
+		if n.Title != "" {
+			content = strings.TrimSpace("### " + n.Title + "\n\n" + n.Body)
+		}
+		return "<Callout" + formatAttributes("icon", icon, "theme", n.Type) + ">\n" + content + "\n</Callout>"
+	}
+
+	name, value := magicBlockValue(n)
+	return encodeMagicBlock(name, value)
+}
+
+func formatTable(n *Table) string {
+	escape := func(cells []string) string {
+		escaped := make([]string, len(n.Header))
+		for i := range escaped {
+			if i < len(cells) {
+				escaped[i] = strings.ReplaceAll(strings.ReplaceAll(cells[i], "|", `\|`), "\n", "<br>")
+			}
+		}
+		return "| " + strings.Join(escaped, " | ") + " |"
+	}
+
+	delimiters := make([]string, len(n.Header))
+	for i := range delimiters {
+		align := ""
+		if i < len(n.Align) {
+			align = n.Align[i]
+		}
+
+		switch align {
+		case AlignLeft:
+			delimiters[i] = ":---"
+		case AlignCenter:
+			delimiters[i] = ":---:"
+		case AlignRight:
+			delimiters[i] = "---:"
+		default:
+			delimiters[i] = "---"
+		}
+	}
+
+	lines := []string{escape(n.Header), "| " + strings.Join(delimiters, " | ") + " |"}
+	for _, row := range n.Rows {
+		lines = append(lines, escape(row))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatImage(n *Image) string {
+	switch n.Syntax {
+	case SyntaxMarkdown:
+		title := ""
+		if n.Title != "" {
+			title = ` "` + n.Title + `"`
+		}
+		return "![" + n.Alt + "](" + n.Src + title + ")"
+
+	case SyntaxMDX:
+		attrs := formatAttributes("src", n.Src, "alt", n.Alt, "title", n.Title, "caption", n.Caption)
+		if n.Width > 0 {
+			attrs += formatAttributes("width", strconv.Itoa(n.Width))
+		}
+		if n.Height > 0 {
+			attrs += formatAttributes("height", strconv.Itoa(n.Height))
+		}
+		return "<Image" + attrs + " />"
+	}
+
+	name, value := magicBlockValue(n)
+	return encodeMagicBlock(name, value)
+}
+
+func formatList(n *List) string {
+	items := make([]string, len(n.Items))
+	for i, item := range n.Items {
+		marker := string(n.Marker) + " "
+		if n.Ordered {
+			marker = strconv.Itoa(n.Start+i) + string(n.Marker) + " "
+		}
+
+		lines := strings.Split(item.Content, "\n")
+		for j := 1; j < len(lines); j++ {
+			if lines[j] != "" {
+				lines[j] = strings.Repeat(" ", len(marker)) + lines[j]
+			}
+		}
+		items[i] = marker + strings.Join(lines, "\n")
+	}
+
+	separator := "\n"
+	if n.Loose {
+		separator = "\n\n"
+	}
+	return strings.Join(items, separator)
+}
+
+// formatAttributes renders name/value pairs as JSX attributes, skipping empty values
+func formatAttributes(pairs ...string) string {
+	result := ""
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			result += " " + pairs[i] + `="` + strings.ReplaceAll(pairs[i+1], `"`, "&quot;") + `"`
+		}
+	}
+	return result
+}
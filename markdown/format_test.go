@@ -0,0 +1,68 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocument_String(t *testing.T) {
+	t.Run("round trips unmodified documents", func(t *testing.T) {
+		for _, src := range []string{
+			readFixture(t, "body.md"),
+			"",
+			"\n\n",
+			"no trailing newline",
+			"\n\n# Leading blank lines\n\n\n\nand extra blank lines   \n  \n",
+			"```\nunclosed fence",
+			"[block:code]\nnot closed",
+		} {
+			assert.Equal(t, src, Parse(src).String())
+		}
+	})
+
+	t.Run("rewrites only modified nodes", func(t *testing.T) {
+		doc := Parse("#   Title   #\n\n> 📘 Note\n>\n> Body\n\n[block:callout]\n{\"type\":\"info\",\"title\":\"Old\",\"body\":\"text\"}\n[/block]\n")
+
+		doc.Children[2].(*Callout).Title = "New"
+
+		assert.Equal(t, "#   Title   #\n\n> 📘 Note\n>\n> Body\n\n[block:callout]\n{\n  \"type\": \"info\",\n  \"title\": \"New\",\n  \"body\": \"text\"\n}\n[/block]\n", doc.String())
+	})
+
+	t.Run("converts nodes by changing their syntax", func(t *testing.T) {
+		doc := Parse("[block:callout]\n{\"type\":\"warning\",\"title\":\"Careful\",\"body\":\"Hot <surface>\"}\n[/block]")
+
+		callout := doc.Children[0].(*Callout)
+		callout.Syntax = SyntaxMarkdown
+		assert.Equal(t, "> 🚧 Careful\n>\n> Hot <surface>", doc.String())
+
+		callout.Syntax = SyntaxMDX
+		assert.Equal(t, "<Callout icon=\"🚧\" theme=\"warning\">\n### Careful\n\nHot <surface>\n</Callout>", doc.String())
+
+		reparsed := Parse(doc.String()).Children[0].(*Callout)
+		assert.Equal(t, "Careful", reparsed.Title)
+		assert.Equal(t, "Hot <surface>", reparsed.Body)
+	})
+
+	t.Run("formats new documents", func(t *testing.T) {
+		doc := &Document{Children: []Node{
+			&Heading{Level: 2, Text: "Parameters"},
+			&Table{
+				Header: []string{"Name", "Type"},
+				Align:  []string{AlignLeft, AlignNone},
+				Rows:   [][]string{{"a|b", "string"}},
+			},
+			&CodeTabs{Syntax: SyntaxMagicBlock, Tabs: []*CodeBlock{{Language: "go", Code: "fmt.Println()"}}},
+			&List{Ordered: true, Start: 3, Marker: '.', Items: []*ListItem{{Content: "three\ncontinued"}, {Content: "four"}}},
+		}}
+
+		assert.Equal(t, "## Parameters\n\n| Name | Type |\n| :--- | --- |\n| a\\|b | string |\n\n[block:code]\n{\n  \"codes\": [\n    {\n      \"code\": \"fmt.Println()\",\n      \"language\": \"go\"\n    }\n  ]\n}\n[/block]\n\n3. three\n   continued\n4. four\n", doc.String())
+	})
+
+	t.Run("separates moved and inserted nodes", func(t *testing.T) {
+		doc := Parse("first\n\n\nsecond\n")
+		doc.Children = []Node{doc.Children[1], &ThematicBreak{}, doc.Children[0]}
+
+		assert.Equal(t, "second\n\n---\n\nfirst\n", doc.String())
+	})
+}
@@ -0,0 +1,263 @@
+package markdown
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Link is a link or image reference found in a body
+type Link struct {
+	// URL is the destination of the link, exactly as written
+	URL string
+
+	// Text is the label of the link or the alt text of an image
+	Text string
+
+	// Line is the 1-based line of the body the link was found on
+	Line int
+
+	// Image reports whether the link is the source of an image
+	Image bool
+}
+
+var (
+	htmlLinkAttribute = regexp.MustCompile(`(?i)\s(href|src)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	linkDefinition    = regexp.MustCompile(`^ {0,3}\[([^\]]+)\]:\s*<?([^\s>]+)>?`)
+)
+
+// ExtractLinks returns the links of a ReadMe-flavored Markdown body in document order: inline
+// links and images, autolinks, link definitions, href and src attributes of HTML, and the URLs of
+// image and embed components. Links inside code are ignored.
+func ExtractLinks(body string) []*Link {
+	result := []*Link{}
+
+	for _, node := range Parse(body).Children {
+		line := Line(node)
+
+		if raw, ok := linkSource(node); ok {
+			for _, span := range scanLinks(raw) {
+				result = append(result, &Link{
+					URL:   raw[span.start:span.end],
+					Text:  span.text,
+					Line:  line + strings.Count(raw[:span.start], "\n"),
+					Image: span.image,
+				})
+			}
+			continue
+		}
+
+		for _, field := range linkFields(node) {
+			for _, span := range scanLinks(*field.text) {
+				result = append(result, &Link{URL: (*field.text)[span.start:span.end], Text: span.text, Line: line, Image: span.image})
+			}
+			if field.url != nil && *field.url != "" {
+				result = append(result, &Link{URL: *field.url, Text: field.label, Line: line, Image: field.image})
+			}
+		}
+	}
+
+	return result
+}
+
+// RewriteLinks calls rewrite with the destination of every link of the body, as found by
+// ExtractLinks, and replaces it with the result. Only the nodes with rewritten links change; the
+// rest of the body is kept as is. It returns the new body and the number of links that changed.
+func RewriteLinks(body string, rewrite func(url string) string) (string, int) {
+	doc := Parse(body)
+	count := 0
+
+	replace := func(text string) string {
+		result := strings.Builder{}
+		last := 0
+		for _, span := range scanLinks(text) {
+			if span.start < last {
+				continue
+			}
+			url := text[span.start:span.end]
+			if rewritten := rewrite(url); rewritten != url {
+				result.WriteString(text[last:span.start])
+				result.WriteString(rewritten)
+				last = span.end
+				count++
+			}
+		}
+		result.WriteString(text[last:])
+		return result.String()
+	}
+
+	for _, node := range doc.Children {
+		if raw, ok := linkSource(node); ok {
+			// the canonical form is unchanged, so the node serializes to the rewritten source
+			node.meta().raw = replace(raw)
+			continue
+		}
+
+		for _, field := range linkFields(node) {
+			*field.text = replace(*field.text)
+			if field.url != nil && *field.url != "" {
+				if rewritten := rewrite(*field.url); rewritten != *field.url {
+					*field.url = rewritten
+					count++
+				}
+			}
+		}
+	}
+
+	if count == 0 {
+		return body, 0
+	}
+
+	return doc.String(), count
+}
+
+// linkSource returns the source of nodes whose links can be found by scanning the Markdown they
+// were parsed from
+func linkSource(node Node) (string, bool) {
+	meta := node.meta()
+
+	switch n := node.(type) {
+	case *Paragraph, *Blockquote, *List, *HTML:
+		return meta.raw, true
+	case *Heading:
+		return meta.raw, n.Syntax == SyntaxMarkdown
+	case *Callout:
+		return meta.raw, n.Syntax == SyntaxMarkdown
+	case *Table:
+		return meta.raw, n.Syntax == SyntaxMarkdown
+	case *Image:
+		return meta.raw, n.Syntax == SyntaxMarkdown
+	}
+
+	return "", false
+}
+
+// linkField is a field of a node that contains links
+type linkField struct {
+	// text is inline Markdown that may contain links
+	text *string
+
+	// url is a field holding a single link, with its label
+	url   *string
+	label string
+	image bool
+}
+
+// linkFields returns the fields of magic blocks and components that contain links
+func linkFields(node Node) []linkField {
+	empty := ""
+
+	switch n := node.(type) {
+	case *Heading:
+		return []linkField{{text: &n.Text}}
+	case *Callout:
+		return []linkField{{text: &n.Title}, {text: &n.Body}}
+	case *Table:
+		result := []linkField{}
+		for i := range n.Header {
+			result = append(result, linkField{text: &n.Header[i]})
+		}
+		for _, row := range n.Rows {
+			for i := range row {
+				result = append(result, linkField{text: &row[i]})
+			}
+		}
+		return result
+	case *Image:
+		return []linkField{{text: &empty, url: &n.Src, label: n.Alt, image: true}, {text: &n.Caption}}
+	case *Embed:
+		return []linkField{{text: &empty, url: &n.URL, label: n.Title}}
+	}
+
+	return nil
+}
+
+// linkSpan locates the destination of a link within a text
+type linkSpan struct {
+	start int
+	end   int
+	text  string
+	image bool
+}
+
+// scanLinks finds the links of inline Markdown text
+func scanLinks(text string) []linkSpan {
+	result := []linkSpan{}
+	add := func(start int, url string, label string, image bool) {
+		result = append(result, linkSpan{start: start, end: start + len(url), text: label, image: image})
+	}
+
+	offset := 0
+	for _, definition := range strings.Split(text, "\n") {
+		if match := linkDefinition.FindStringSubmatchIndex(definition); match != nil {
+			add(offset+match[4], definition[match[4]:match[5]], definition[match[2]:match[3]], false)
+		}
+		offset += len(definition) + 1
+	}
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+
+		switch text[i] {
+		case '\\':
+			i += 2
+			continue
+
+		case '`':
+			if _, n, ok := codeSpan(rest); ok {
+				i += n
+				continue
+			}
+
+		case '!', '[':
+			image := text[i] == '!'
+			start := i
+			if image {
+				start++
+			}
+			if strings.HasPrefix(text[start:], "[") {
+				if label, destination, _, n, ok := link(text[start:]); ok {
+					// the destination follows the "[label](" prefix
+					prefix := start + len(label) + 3
+					add(prefix+strings.Index(text[prefix:], destination), destination, label, image)
+					if !image {
+						// links may wrap images, ex. [![badge](badge.svg)](https://ci)
+						for _, span := range scanLinks(label) {
+							span.start += start + 1
+							span.end += start + 1
+							result = append(result, span)
+						}
+					}
+					i = start + n
+					continue
+				}
+			}
+
+		case '<':
+			if match := inlineAutolink.FindStringSubmatch(rest); match != nil {
+				add(i+1, match[1], match[1], false)
+				i += len(match[0])
+				continue
+			}
+			if tag := inlineTag.FindString(rest); tag != "" {
+				for _, attribute := range htmlLinkAttribute.FindAllStringSubmatchIndex(tag, -1) {
+					start, end := attribute[4], attribute[5]
+					if start == -1 {
+						start, end = attribute[6], attribute[7]
+					}
+					add(i+start, tag[start:end], "", strings.EqualFold(tag[attribute[2]:attribute[3]], "src"))
+				}
+				i += len(tag)
+				continue
+			}
+		}
+
+		i++
+	}
+
+	sort.SliceStable(result, func(a, b int) bool {
+		return result[a].start < result[b].start
+	})
+
+	return result
+}
@@ -0,0 +1,61 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractLinks(t *testing.T) {
+	t.Run("finds markdown links", func(t *testing.T) {
+		links := ExtractLinks("# See [setup](doc:setup)\n\nRead the [guide](/docs/guide#intro \"Guide\")\nand <https://readme.com>.\n\n- [![badge](badge.svg)](https://ci.example.com)\n- `[not](a-link)`\n\n[ref]: https://example.com/ref\n")
+
+		assert.Equal(t, []*Link{
+			{URL: "doc:setup", Text: "setup", Line: 1},
+			{URL: "/docs/guide#intro", Text: "guide", Line: 3},
+			{URL: "https://readme.com", Text: "https://readme.com", Line: 4},
+			{URL: "badge.svg", Text: "badge", Line: 6, Image: true},
+			{URL: "https://ci.example.com", Text: "![badge](badge.svg)", Line: 6},
+			{URL: "https://example.com/ref", Text: "ref", Line: 9},
+		}, links)
+	})
+
+	t.Run("finds links in magic blocks and html", func(t *testing.T) {
+		links := ExtractLinks("```\n[skip](me)\n```\n\n[block:callout]\n{\"type\": \"info\", \"body\": \"Go to [the reference](ref:get-pets).\"}\n[/block]\n\n[block:image]\n{\"images\": [{\"image\": [\"https://files.readme.io/a.png\", \"a\"]}]}\n[/block]\n\n<div>\n  <a href=\"/page/about\">About</a>\n</div>\n")
+
+		assert.Equal(t, []*Link{
+			{URL: "ref:get-pets", Text: "the reference", Line: 5},
+			{URL: "https://files.readme.io/a.png", Text: "a", Line: 9, Image: true},
+			{URL: "/page/about", Line: 14},
+		}, links)
+	})
+}
+
+func TestRewriteLinks(t *testing.T) {
+	rename := func(url string) string {
+		return strings.Replace(url, "old", "new", 1)
+	}
+
+	t.Run("rewrites link destinations only", func(t *testing.T) {
+		body, count := RewriteLinks("# The old way\n\nSee [old](doc:old) or <a href='/docs/old'>old</a>.\n\n```\n[old](doc:old)\n```\n\n[old]: /docs/old\n", rename)
+
+		assert.Equal(t, 3, count)
+		assert.Equal(t, "# The old way\n\nSee [old](doc:new) or <a href='/docs/new'>old</a>.\n\n```\n[old](doc:old)\n```\n\n[old]: /docs/new\n", body)
+	})
+
+	t.Run("rewrites magic blocks", func(t *testing.T) {
+		body, count := RewriteLinks("Intro\n\n[block:callout]\n{\"type\": \"info\", \"body\": \"See [old](doc:old)\"}\n[/block]\n", rename)
+
+		assert.Equal(t, 1, count)
+		assert.Equal(t, "Intro\n\n[block:callout]\n{\n  \"type\": \"info\",\n  \"title\": \"\",\n  \"body\": \"See [old](doc:new)\"\n}\n[/block]\n", body)
+	})
+
+	t.Run("leaves bodies without matches untouched", func(t *testing.T) {
+		src := "[block:callout]\n{\"type\":\"info\",\"body\":\"[x](doc:other)\"}\n[/block]"
+
+		body, count := RewriteLinks(src, rename)
+		assert.Equal(t, 0, count)
+		assert.Equal(t, src, body)
+	})
+}
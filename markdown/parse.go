@@ -0,0 +1,591 @@
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	magicBlockStart  = regexp.MustCompile(`^\[block:([\w-]+)\]\s*$`)
+	magicBlockEnd    = regexp.MustCompile(`^\[/block\]\s*$`)
+	fenceStart       = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \t]*([^`]*)$")
+	atxHeading       = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	thematicBreak    = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	blockquoteLine   = regexp.MustCompile(`^ {0,3}>`)
+	mdxComponent     = regexp.MustCompile(`^<(Callout|Image|Embed|Recipe)\b`)
+	htmlStart        = regexp.MustCompile(`^ {0,3}<(?:[A-Za-z]|!--|/[A-Za-z])`)
+	tableDelimiter   = regexp.MustCompile(`^ {0,3}\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	listMarker       = regexp.MustCompile(`^( {0,3})(?:([-+*])|(\d{1,9})([.)]))(?:[ \t]+|$)`)
+	standaloneImage  = regexp.MustCompile(`^!\[([^\]]*)\]\(\s*(\S+?)(?:\s+"([^"]*)")?\s*\)$`)
+	markdownEmbed    = regexp.MustCompile(`^\[([^\]]*)\]\(\s*(\S+?)\s+"@embed"\s*\)$`)
+	mdxAttribute     = regexp.MustCompile(`(\w+)=(?:"([^"]*)"|'([^']*)'|\{([^}]*)\})`)
+	calloutHeadingMD = regexp.MustCompile(`^#{1,6}\s+(.*)$`)
+)
+
+// calloutIcons maps the emoji that start a Markdown callout to callout types
+var calloutIcons = map[string]string{
+	"📘":       CalloutInfo,
+	"👍":       CalloutSuccess,
+	"🚧":       CalloutWarning,
+	"❗\ufe0f": CalloutDanger,
+	"❗":       CalloutDanger,
+}
+
+// defaultCalloutIcons maps callout types to the emoji used when a callout has no icon
+var defaultCalloutIcons = map[string]string{
+	CalloutInfo:    "📘",
+	CalloutSuccess: "👍",
+	CalloutWarning: "🚧",
+	CalloutDanger:  "❗\ufe0f",
+}
+
+type parser struct {
+	lines []string
+	pos   int
+}
+
+// Parse parses a ReadMe-flavored Markdown body. Parsing never fails: text that isn't understood is
+// kept as paragraphs, HTML or raw blocks.
+func Parse(src string) *Document {
+	p := &parser{lines: strings.Split(src, "\n")}
+	doc := &Document{Children: []Node{}}
+	doc.parsed = true
+
+	end := 0
+	for p.pos < len(p.lines) {
+		if isBlank(p.lines[p.pos]) {
+			p.pos++
+			continue
+		}
+
+		start := p.pos
+		node := p.block()
+
+		meta := node.meta()
+		meta.parsed = true
+		meta.line = start + 1
+		meta.raw = strings.Join(p.lines[start:p.pos], "\n")
+		meta.canonical = format(node)
+
+		if len(doc.Children) == 0 {
+			meta.first = true
+			meta.lead = joinLines(p.lines[0:start])
+		} else {
+			meta.lead = "\n" + joinLines(p.lines[end:start])
+		}
+
+		doc.Children = append(doc.Children, node)
+		end = p.pos
+	}
+
+	if len(doc.Children) == 0 {
+		doc.trailing = src
+	} else if end < len(p.lines) {
+		doc.trailing = "\n" + strings.Join(p.lines[end:], "\n")
+	}
+
+	return doc
+}
+
+// joinLines joins lines, terminating each with a newline
+func joinLines(lines []string) string {
+	result := ""
+	for _, line := range lines {
+		result += line + "\n"
+	}
+	return result
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// indentation returns the width of the leading whitespace of the line, counting tabs as 4 spaces
+func indentation(line string) int {
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4 - width%4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// dedent removes up to width columns of leading whitespace from the line
+func dedent(line string, width int) string {
+	removed := 0
+	for i, r := range line {
+		if removed >= width {
+			return line[i:]
+		}
+		switch r {
+		case ' ':
+			removed++
+		case '\t':
+			removed += 4 - removed%4
+		default:
+			return line[i:]
+		}
+	}
+	return ""
+}
+
+func (p *parser) line() string {
+	return p.lines[p.pos]
+}
+
+func (p *parser) block() Node {
+	line := p.line()
+
+	switch {
+	case magicBlockStart.MatchString(line):
+		if node := p.magicBlock(); node != nil {
+			return node
+		}
+	case fenceStart.MatchString(line):
+		return p.code()
+	case atxHeading.MatchString(line):
+		match := atxHeading.FindStringSubmatch(line)
+		p.pos++
+		return &Heading{Level: len(match[1]), Text: match[2]}
+	case thematicBreak.MatchString(line):
+		p.pos++
+		return &ThematicBreak{}
+	case blockquoteLine.MatchString(line):
+		return p.blockquote()
+	case mdxComponent.MatchString(line):
+		return p.component()
+	case htmlStart.MatchString(line):
+		return p.html()
+	case p.isTableStart(p.pos):
+		return p.table()
+	case listMarker.MatchString(line):
+		return p.list()
+	}
+
+	return p.paragraph()
+}
+
+// magicBlock parses a [block:name] ... [/block] magic block, returning nil if it is never closed
+func (p *parser) magicBlock() Node {
+	name := magicBlockStart.FindStringSubmatch(p.line())[1]
+
+	for end := p.pos + 1; end < len(p.lines); end++ {
+		if magicBlockEnd.MatchString(p.lines[end]) {
+			content := strings.Join(p.lines[p.pos+1:end], "\n")
+			p.pos = end + 1
+			return decodeMagicBlock(name, content)
+		}
+	}
+
+	return nil
+}
+
+// code parses one fenced code block, or several adjacent ones as code tabs
+func (p *parser) code() Node {
+	tabs := []*CodeBlock{p.fence()}
+
+	for p.pos < len(p.lines) && fenceStart.MatchString(p.line()) {
+		tabs = append(tabs, p.fence())
+	}
+
+	if len(tabs) == 1 {
+		return tabs[0]
+	}
+
+	return &CodeTabs{Tabs: tabs}
+}
+
+func (p *parser) fence() *CodeBlock {
+	match := fenceStart.FindStringSubmatch(p.line())
+	indent, fence, info := len(match[1]), match[2], strings.TrimSpace(match[3])
+	p.pos++
+
+	block := &CodeBlock{}
+	if info != "" {
+		fields := strings.SplitN(info, " ", 2)
+		block.Language = fields[0]
+		if len(fields) > 1 {
+			block.Name = strings.TrimSpace(fields[1])
+		}
+	}
+
+	code := []string{}
+	for ; p.pos < len(p.lines); p.pos++ {
+		line := p.line()
+		trimmed := strings.TrimSpace(line)
+		if indentation(line) < 4 && strings.HasPrefix(trimmed, fence[:1]) && strings.Trim(trimmed, fence[:1]) == "" && len(trimmed) >= len(fence) {
+			p.pos++
+			break
+		}
+		code = append(code, dedent(line, indent))
+	}
+
+	block.Code = strings.Join(code, "\n")
+	return block
+}
+
+func (p *parser) blockquote() Node {
+	content := []string{}
+	for ; p.pos < len(p.lines) && blockquoteLine.MatchString(p.line()); p.pos++ {
+		line := strings.TrimLeft(p.line(), " ")[1:]
+		line = strings.TrimPrefix(line, " ")
+		content = append(content, line)
+	}
+
+	first := strings.TrimSpace(content[0])
+	for icon, calloutType := range calloutIcons {
+		if !strings.HasPrefix(first, icon) {
+			continue
+		}
+
+		// a shorter icon can be a prefix of a longer one that includes a variation selector
+		rest := strings.TrimPrefix(first, icon)
+		if strings.HasPrefix(rest, "\ufe0f") {
+			continue
+		}
+
+		return &Callout{
+			Type:  calloutType,
+			Icon:  icon,
+			Title: strings.TrimSpace(rest),
+			Body:  trimBlankLines(content[1:]),
+		}
+	}
+
+	return &Blockquote{Text: strings.Join(content, "\n")}
+}
+
+// component parses an MDX-style component such as <Callout> or <Image />
+func (p *parser) component() Node {
+	name := mdxComponent.FindStringSubmatch(p.line())[1]
+	start := p.pos
+
+	// the opening tag may span several lines
+	tag := ""
+	for ; p.pos < len(p.lines); p.pos++ {
+		tag += p.line() + "\n"
+		if strings.Contains(p.line(), ">") {
+			break
+		}
+	}
+	p.pos++
+
+	attrs := parseAttributes(tag)
+	selfClosing := strings.Contains(tag, "/>")
+
+	body := []string{}
+	if !selfClosing {
+		closed := false
+		for ; p.pos < len(p.lines); p.pos++ {
+			if strings.TrimSpace(p.line()) == "</"+name+">" {
+				p.pos++
+				closed = true
+				break
+			}
+			body = append(body, p.line())
+		}
+
+		if !closed {
+			p.pos = start
+			return p.html()
+		}
+	}
+
+	switch name {
+	case "Callout":
+		callout := &Callout{Syntax: SyntaxMDX, Icon: attrs["icon"], Type: attrs["theme"]}
+		if callout.Type == "" {
+			callout.Type = calloutIcons[callout.Icon]
+		}
+
+		lines := dedentLines(body)
+		for len(lines) > 0 && isBlank(lines[0]) {
+			lines = lines[1:]
+		}
+		if len(lines) > 0 {
+			if match := calloutHeadingMD.FindStringSubmatch(lines[0]); match != nil {
+				callout.Title = strings.TrimSpace(match[1])
+				lines = lines[1:]
+			}
+		}
+		callout.Body = trimBlankLines(lines)
+		return callout
+
+	case "Image":
+		width, _ := strconv.Atoi(attrs["width"])
+		height, _ := strconv.Atoi(attrs["height"])
+		return &Image{
+			Syntax:  SyntaxMDX,
+			Src:     attrs["src"],
+			Alt:     attrs["alt"],
+			Title:   attrs["title"],
+			Caption: attrs["caption"],
+			Width:   width,
+			Height:  height,
+		}
+
+	case "Embed":
+		return &Embed{
+			Syntax:   SyntaxMDX,
+			URL:      attrs["url"],
+			Title:    attrs["title"],
+			Provider: attrs["provider"],
+			HTML:     attrs["html"],
+		}
+
+	default:
+		return &Recipe{
+			Syntax: SyntaxMDX,
+			Slug:   attrs["slug"],
+			Title:  attrs["title"],
+			Emoji:  attrs["emoji"],
+		}
+	}
+}
+
+func parseAttributes(tag string) map[string]string {
+	attrs := make(map[string]string)
+	for _, match := range mdxAttribute.FindAllStringSubmatch(tag, -1) {
+		value := match[2]
+		if value == "" {
+			value = match[3]
+		}
+		if value == "" {
+			value = strings.Trim(strings.TrimSpace(match[4]), "\"'`")
+		}
+		attrs[match[1]] = strings.ReplaceAll(value, "&quot;", `"`)
+	}
+	return attrs
+}
+
+func (p *parser) html() Node {
+	lines := []string{}
+	for ; p.pos < len(p.lines) && !isBlank(p.line()); p.pos++ {
+		lines = append(lines, p.line())
+	}
+	return &HTML{HTML: strings.Join(lines, "\n")}
+}
+
+func (p *parser) isTableStart(i int) bool {
+	return i+1 < len(p.lines) && strings.Contains(p.lines[i], "|") && strings.Contains(p.lines[i+1], "-") && tableDelimiter.MatchString(p.lines[i+1])
+}
+
+func (p *parser) table() Node {
+	table := &Table{Header: splitTableRow(p.line())}
+	p.pos++
+
+	for _, cell := range splitTableRow(p.line()) {
+		left, right := strings.HasPrefix(cell, ":"), strings.HasSuffix(cell, ":")
+		switch {
+		case left && right:
+			table.Align = append(table.Align, AlignCenter)
+		case left:
+			table.Align = append(table.Align, AlignLeft)
+		case right:
+			table.Align = append(table.Align, AlignRight)
+		default:
+			table.Align = append(table.Align, AlignNone)
+		}
+	}
+	p.pos++
+
+	for ; p.pos < len(p.lines) && !isBlank(p.line()) && strings.Contains(p.line(), "|"); p.pos++ {
+		row := splitTableRow(p.line())
+		for len(row) < len(table.Header) {
+			row = append(row, "")
+		}
+		table.Rows = append(table.Rows, row[:len(table.Header)])
+	}
+
+	for len(table.Align) < len(table.Header) {
+		table.Align = append(table.Align, AlignNone)
+	}
+	table.Align = table.Align[:len(table.Header)]
+
+	return table
+}
+
+// splitTableRow splits a pipe table row into trimmed cells, unescaping escaped pipes
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	cells := []string{}
+	cell := strings.Builder{}
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+func (p *parser) list() Node {
+	first := listMarker.FindStringSubmatch(p.line())
+	list := &List{Ordered: first[3] != ""}
+	if list.Ordered {
+		list.Start, _ = strconv.Atoi(first[3])
+		list.Marker = first[4][0]
+	} else {
+		list.Marker = first[2][0]
+	}
+
+	indent := len(first[1])
+	var item []string
+	contentIndent := 0
+
+	flush := func() {
+		if item != nil {
+			list.Items = append(list.Items, &ListItem{Content: trimBlankLines(item)})
+		}
+	}
+
+	sibling := func(line string) []string {
+		match := listMarker.FindStringSubmatch(line)
+		if match == nil || len(match[1]) != indent || thematicBreak.MatchString(line) {
+			return nil
+		}
+		if list.Ordered && (match[3] == "" || match[4][0] != list.Marker) {
+			return nil
+		}
+		if !list.Ordered && (match[2] == "" || match[2][0] != list.Marker) {
+			return nil
+		}
+		return match
+	}
+
+	for p.pos < len(p.lines) {
+		line := p.line()
+
+		if match := sibling(line); match != nil {
+			flush()
+			contentIndent = len(match[0])
+			if strings.TrimSpace(line[len(match[0]):]) == "" {
+				contentIndent = len(match[0]) + 1
+			}
+			item = []string{line[len(match[0]):]}
+			p.pos++
+			continue
+		}
+
+		if isBlank(line) {
+			next := p.pos
+			for next < len(p.lines) && isBlank(p.lines[next]) {
+				next++
+			}
+
+			if next == len(p.lines) || (indentation(p.lines[next]) < contentIndent && sibling(p.lines[next]) == nil) {
+				break
+			}
+
+			if sibling(p.lines[next]) != nil {
+				list.Loose = true
+			}
+
+			for ; p.pos < next; p.pos++ {
+				item = append(item, "")
+			}
+			continue
+		}
+
+		if indentation(line) >= contentIndent {
+			item = append(item, dedent(line, contentIndent))
+			p.pos++
+			continue
+		}
+
+		// lazy continuation of the item's last paragraph
+		if len(item) > 0 && !isBlank(item[len(item)-1]) && !p.interruptsParagraph(p.pos) {
+			item = append(item, strings.TrimSpace(line))
+			p.pos++
+			continue
+		}
+
+		break
+	}
+
+	flush()
+	return list
+}
+
+// interruptsParagraph reports whether the line starts a block that ends a paragraph
+func (p *parser) interruptsParagraph(i int) bool {
+	line := p.lines[i]
+	return isBlank(line) ||
+		magicBlockStart.MatchString(line) ||
+		fenceStart.MatchString(line) ||
+		atxHeading.MatchString(line) ||
+		thematicBreak.MatchString(line) ||
+		blockquoteLine.MatchString(line) ||
+		mdxComponent.MatchString(line) ||
+		listMarker.MatchString(line) ||
+		p.isTableStart(i)
+}
+
+func (p *parser) paragraph() Node {
+	lines := []string{p.line()}
+	for p.pos++; p.pos < len(p.lines) && !p.interruptsParagraph(p.pos); p.pos++ {
+		lines = append(lines, p.line())
+	}
+
+	text := strings.Join(lines, "\n")
+	trimmed := strings.TrimSpace(text)
+
+	if match := standaloneImage.FindStringSubmatch(trimmed); match != nil {
+		return &Image{Alt: match[1], Src: match[2], Title: match[3]}
+	}
+
+	if match := markdownEmbed.FindStringSubmatch(trimmed); match != nil {
+		return &Embed{Title: match[1], URL: match[2]}
+	}
+
+	return &Paragraph{Text: text}
+}
+
+// trimBlankLines joins the lines, dropping leading and trailing blank lines
+func trimBlankLines(lines []string) string {
+	for len(lines) > 0 && isBlank(lines[0]) {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && isBlank(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// dedentLines removes the indentation common to every non-blank line
+func dedentLines(lines []string) []string {
+	common := -1
+	for _, line := range lines {
+		if isBlank(line) {
+			continue
+		}
+		if width := indentation(line); common == -1 || width < common {
+			common = width
+		}
+	}
+
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		result[i] = dedent(line, common)
+	}
+	return result
+}
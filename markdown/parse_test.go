@@ -0,0 +1,174 @@
+package markdown
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readFixture(t *testing.T, name string) string {
+	data, err := ioutil.ReadFile("./fixtures/" + name)
+	if err != nil {
+		t.Fatalf("could not read fixture: %v", err)
+	}
+	return string(data)
+}
+
+func TestParse(t *testing.T) {
+	doc := Parse(readFixture(t, "body.md"))
+
+	types := []string{}
+	for _, child := range doc.Children {
+		switch child.(type) {
+		case *Heading:
+			types = append(types, "heading")
+		case *Paragraph:
+			types = append(types, "paragraph")
+		case *Callout:
+			types = append(types, "callout")
+		case *Blockquote:
+			types = append(types, "blockquote")
+		case *CodeTabs:
+			types = append(types, "codetabs")
+		case *Table:
+			types = append(types, "table")
+		case *Image:
+			types = append(types, "image")
+		case *Embed:
+			types = append(types, "embed")
+		case *Recipe:
+			types = append(types, "recipe")
+		case *List:
+			types = append(types, "list")
+		case *ThematicBreak:
+			types = append(types, "break")
+		case *HTML:
+			types = append(types, "html")
+		case *RawBlock:
+			types = append(types, "raw")
+		default:
+			types = append(types, "other")
+		}
+	}
+
+	t.Run("parses every block", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"heading", "paragraph", "heading",
+			"callout", "callout", "blockquote",
+			"codetabs", "codetabs",
+			"table", "table",
+			"image", "image",
+			"embed", "callout", "recipe", "image", "recipe",
+			"list", "list", "break", "html", "raw",
+		}, types)
+	})
+
+	t.Run("parses headings", func(t *testing.T) {
+		assert.Equal(t, &Heading{Syntax: SyntaxMagicBlock, Level: 1, Text: "Authentication"}, stripMeta(doc.Children[0]))
+		assert.Equal(t, &Heading{Level: 2, Text: "Getting a key"}, stripMeta(doc.Children[2]))
+	})
+
+	t.Run("parses callouts in every syntax", func(t *testing.T) {
+		assert.Equal(t, &Callout{Syntax: SyntaxMagicBlock, Type: CalloutWarning, Title: "Keep it secret", Body: "Never commit keys to git."}, stripMeta(doc.Children[3]))
+		assert.Equal(t, &Callout{Type: CalloutInfo, Icon: "📘", Title: "Tip", Body: "Keys can be rotated at any time."}, stripMeta(doc.Children[4]))
+		assert.Equal(t, &Callout{Syntax: SyntaxMDX, Type: CalloutSuccess, Icon: "👍", Title: "All set", Body: "You're ready to make requests."}, stripMeta(doc.Children[13]))
+	})
+
+	t.Run("parses code tabs", func(t *testing.T) {
+		tabs := doc.Children[6].(*CodeTabs)
+		assert.Equal(t, SyntaxMarkdown, tabs.Syntax)
+		assert.Len(t, tabs.Tabs, 2)
+		assert.Equal(t, "shell", tabs.Tabs[0].Language)
+		assert.Equal(t, "cURL", tabs.Tabs[0].Name)
+		assert.Equal(t, "const readme = require('readme');", tabs.Tabs[1].Code)
+
+		legacy := doc.Children[7].(*CodeTabs)
+		assert.Equal(t, SyntaxMagicBlock, legacy.Syntax)
+		assert.Equal(t, "echo hi", legacy.Tabs[0].Code)
+	})
+
+	t.Run("parses tables", func(t *testing.T) {
+		table := doc.Children[8].(*Table)
+		assert.Equal(t, []string{"Name", "Description"}, table.Header)
+		assert.Equal(t, []string{AlignLeft, AlignRight}, table.Align)
+		assert.Equal(t, [][]string{{"key", "The API key | secret"}, {"version", "Optional"}}, table.Rows)
+
+		parameters := doc.Children[9].(*Table)
+		assert.Equal(t, SyntaxMagicBlock, parameters.Syntax)
+		assert.Equal(t, []string{"Code", "Meaning"}, parameters.Header)
+		assert.Equal(t, [][]string{{"401", "Unauthorized"}}, parameters.Rows)
+	})
+
+	t.Run("parses images, embeds and recipes", func(t *testing.T) {
+		assert.Equal(t, &Image{Src: "https://files.readme.io/dash.png", Alt: "Dashboard", Title: "The dashboard"}, stripMeta(doc.Children[10]))
+		assert.Equal(t, &Image{Syntax: SyntaxMagicBlock, Src: "https://files.readme.io/keys.png", Alt: "keys.png", Caption: "Your keys", Width: 800, Height: 600}, stripMeta(doc.Children[11]))
+		assert.Equal(t, &Embed{Title: "Intro video", URL: "https://www.youtube.com/watch?v=abc"}, stripMeta(doc.Children[12]))
+		assert.Equal(t, &Recipe{Syntax: SyntaxMDX, Slug: "first-request", Title: "Make your first request"}, stripMeta(doc.Children[14]))
+		assert.Equal(t, &Image{Syntax: SyntaxMDX, Src: "https://files.readme.io/flow.png", Alt: "Flow", Caption: "Request flow"}, stripMeta(doc.Children[15]))
+		assert.Equal(t, &Recipe{Syntax: SyntaxMagicBlock, Slug: "rotate-keys", Title: "Rotate keys", Emoji: "🦉"}, stripMeta(doc.Children[16]))
+	})
+
+	t.Run("parses lists", func(t *testing.T) {
+		ordered := doc.Children[17].(*List)
+		assert.True(t, ordered.Ordered)
+		assert.False(t, ordered.Loose)
+		assert.Equal(t, 1, ordered.Start)
+		assert.Len(t, ordered.Items, 3)
+		assert.Equal(t, "Copy it\nsomewhere safe\n\n* nested item", ordered.Items[1].Content)
+
+		unordered := doc.Children[18].(*List)
+		assert.False(t, unordered.Ordered)
+		assert.Equal(t, byte('-'), unordered.Marker)
+		assert.Len(t, unordered.Items, 2)
+	})
+
+	t.Run("keeps unknown magic blocks", func(t *testing.T) {
+		raw := doc.Children[21].(*RawBlock)
+		assert.Equal(t, "unknown-widget", raw.Name)
+		assert.Equal(t, `{ "anything": true }`, raw.Content)
+	})
+
+	t.Run("keeps parameters with invalid sizes", func(t *testing.T) {
+		for _, content := range []string{
+			`{"data": {"h-0": "Code"}, "cols": -1, "rows": 0}`,
+			`{"data": {"h-0": "Code"}, "cols": 1, "rows": -1}`,
+			`{"data": {"h-0": "Code", "0-0": "401"}, "cols": 1000000000, "rows": 1}`,
+			`{"data": {"h-0": "Code", "0-0": "401"}, "cols": 1, "rows": 1000000000}`,
+		} {
+			doc := Parse("[block:parameters]\n" + content + "\n[/block]\n")
+			raw, ok := doc.Children[0].(*RawBlock)
+			if assert.True(t, ok, content) {
+				assert.Equal(t, "parameters", raw.Name)
+				assert.Equal(t, content, raw.Content)
+			}
+		}
+	})
+}
+
+// stripMeta returns a copy of the node without parse metadata, for comparisons
+func stripMeta(node Node) Node {
+	switch n := node.(type) {
+	case *Heading:
+		c := *n
+		c.nodeMeta = nodeMeta{}
+		return &c
+	case *Callout:
+		c := *n
+		c.nodeMeta = nodeMeta{}
+		return &c
+	case *Image:
+		c := *n
+		c.nodeMeta = nodeMeta{}
+		return &c
+	case *Embed:
+		c := *n
+		c.nodeMeta = nodeMeta{}
+		return &c
+	case *Recipe:
+		c := *n
+		c.nodeMeta = nodeMeta{}
+		return &c
+	}
+	return node
+}
@@ -0,0 +1,537 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Highlighter renders code as syntax highlighted HTML. It returns false to fall back to plain,
+// escaped code, ex. for unsupported languages.
+type Highlighter interface {
+	Highlight(language string, code string) (string, bool)
+}
+
+// HighlighterFunc adapts a function to the Highlighter interface
+type HighlighterFunc func(language string, code string) (string, bool)
+
+// Highlight calls f(language, code)
+func (f HighlighterFunc) Highlight(language string, code string) (string, bool) {
+	return f(language, code)
+}
+
+// RenderOptions are the options available when rendering HTML
+type RenderOptions struct {
+	// Highlighter highlights the contents of code blocks. Code is escaped and left plain when nil.
+	Highlighter Highlighter
+
+	// Variables are substituted for <<name>> placeholders. Placeholders without a value are
+	// rendered as a span with the variable name, like ReadMe does for logged out users.
+	Variables map[string]string
+
+	// ClassPrefix is prepended to every CSS class. Defaults to "rdmd-".
+	ClassPrefix string
+}
+
+// ToHTML parses a ReadMe-flavored Markdown body and renders it as HTML
+func ToHTML(body string, opt RenderOptions) string {
+	return RenderHTML(Parse(body), opt)
+}
+
+// RenderHTML renders the node as HTML close to ReadMe's own output. The markup carries no styling;
+// elements that need styling are given classes such as "rdmd-callout rdmd-callout--info".
+func RenderHTML(node Node, opt RenderOptions) string {
+	if opt.ClassPrefix == "" {
+		opt.ClassPrefix = "rdmd-"
+	}
+
+	r := &renderer{opt: opt, out: &strings.Builder{}}
+	r.block(node)
+	return r.out.String()
+}
+
+type renderer struct {
+	opt RenderOptions
+	out *strings.Builder
+}
+
+func (r *renderer) write(s ...string) {
+	for _, part := range s {
+		r.out.WriteString(part)
+	}
+}
+
+// class returns the prefixed class names as a class attribute
+func (r *renderer) class(names ...string) string {
+	for i, name := range names {
+		names[i] = r.opt.ClassPrefix + name
+	}
+	return ` class="` + strings.Join(names, " ") + `"`
+}
+
+func (r *renderer) block(node Node) {
+	switch n := node.(type) {
+	case *Document:
+		for _, child := range n.Children {
+			r.block(child)
+		}
+
+	case *Heading:
+		level := n.Level
+		if level < 1 {
+			level = 1
+		} else if level > 6 {
+			level = 6
+		}
+		tag := "h" + strconv.Itoa(level)
+		r.write("<", tag, r.class("heading"), ` id="`, headingID(n.Text), `">`, r.inline(n.Text), "</", tag, ">\n")
+
+	case *Paragraph:
+		r.write("<p>", r.inline(n.Text), "</p>\n")
+
+	case *ThematicBreak:
+		r.write("<hr>\n")
+
+	case *Blockquote:
+		r.write("<blockquote>\n")
+		r.block(Parse(n.Text))
+		r.write("</blockquote>\n")
+
+	case *List:
+		r.list(n)
+
+	case *CodeBlock:
+		r.code(n)
+
+	case *CodeTabs:
+		r.write("<div", r.class("code-tabs"), ">\n<div", r.class("code-tabs__toolbar"), ">")
+		for i, tab := range n.Tabs {
+			name := tab.Name
+			if name == "" {
+				name = tab.Language
+			}
+			r.write("<button", r.class("code-tabs__tab"), ` data-tab="`, strconv.Itoa(i), `">`, html.EscapeString(name), "</button>")
+		}
+		r.write("</div>\n")
+		for _, tab := range n.Tabs {
+			r.code(tab)
+		}
+		r.write("</div>\n")
+
+	case *Callout:
+		icon := n.Icon
+		if icon == "" {
+			icon = defaultCalloutIcons[n.Type]
+		}
+		r.write("<blockquote", r.class("callout", "callout--"+n.Type), ">\n")
+		r.write("<h3", r.class("callout__title"), "><span", r.class("callout__icon"), ">", icon, "</span>")
+		if n.Title != "" {
+			r.write(" ", r.inline(n.Title))
+		}
+		r.write("</h3>\n")
+		r.block(Parse(n.Body))
+		r.write("</blockquote>\n")
+
+	case *Table:
+		r.table(n)
+
+	case *Image:
+		r.write("<figure", r.class("image"), `><img src="`, html.EscapeString(n.Src), `" alt="`, html.EscapeString(n.Alt), `"`)
+		if n.Title != "" {
+			r.write(` title="`, html.EscapeString(n.Title), `"`)
+		}
+		if n.Width > 0 {
+			r.write(` width="`, strconv.Itoa(n.Width), `"`)
+		}
+		if n.Height > 0 {
+			r.write(` height="`, strconv.Itoa(n.Height), `"`)
+		}
+		r.write(` loading="lazy">`)
+		if n.Caption != "" {
+			r.write("<figcaption>", r.inline(n.Caption), "</figcaption>")
+		}
+		r.write("</figure>\n")
+
+	case *Embed:
+		r.write("<div", r.class("embed"), ">")
+		if n.HTML != "" {
+			r.write(n.HTML)
+		} else {
+			title := n.Title
+			if title == "" {
+				title = n.URL
+			}
+			r.write(`<a href="`, html.EscapeString(n.URL), `" target="_blank" rel="noopener">`, html.EscapeString(title), "</a>")
+		}
+		r.write("</div>\n")
+
+	case *Recipe:
+		r.write("<a", r.class("recipe"), ` href="/recipes/`, html.EscapeString(n.Slug), `">`)
+		if n.Emoji != "" {
+			r.write("<span", r.class("recipe__emoji"), ">", n.Emoji, "</span>")
+		}
+		r.write("<span", r.class("recipe__title"), ">", html.EscapeString(n.Title), "</span></a>\n")
+
+	case *HTML:
+		r.write(n.HTML, "\n")
+
+	case *RawBlock:
+		r.write("<!-- unsupported block: ", html.EscapeString(n.Name), " -->\n")
+	}
+}
+
+func (r *renderer) code(n *CodeBlock) {
+	code, ok := "", false
+	if r.opt.Highlighter != nil {
+		code, ok = r.opt.Highlighter.Highlight(n.Language, n.Code)
+	}
+	if !ok {
+		code = html.EscapeString(n.Code)
+	}
+
+	r.write("<pre", r.class("code"))
+	if n.Language != "" {
+		r.write(` data-lang="`, html.EscapeString(n.Language), `"><code class="language-`, html.EscapeString(n.Language), `">`)
+	} else {
+		r.write("><code>")
+	}
+	r.write(code, "</code></pre>\n")
+}
+
+func (r *renderer) list(n *List) {
+	tag := "ul"
+	if n.Ordered {
+		tag = "ol"
+	}
+
+	r.write("<", tag)
+	if n.Ordered && n.Start != 1 {
+		r.write(` start="`, strconv.Itoa(n.Start), `"`)
+	}
+	r.write(">\n")
+
+	for _, item := range n.Items {
+		doc := Parse(item.Content)
+
+		r.write("<li>")
+		if paragraph, ok := firstParagraph(doc); ok && !n.Loose {
+			// tight list items render their leading paragraph without <p> tags
+			r.write(r.inline(paragraph.Text))
+			if len(doc.Children) > 1 {
+				r.write("\n")
+				r.block(&Document{Children: doc.Children[1:]})
+			}
+		} else {
+			r.write("\n")
+			r.block(doc)
+		}
+		r.write("</li>\n")
+	}
+
+	r.write("</", tag, ">\n")
+}
+
+func firstParagraph(doc *Document) (*Paragraph, bool) {
+	if len(doc.Children) == 0 {
+		return nil, false
+	}
+	paragraph, ok := doc.Children[0].(*Paragraph)
+	return paragraph, ok
+}
+
+func (r *renderer) table(n *Table) {
+	cell := func(tag string, col int, text string) {
+		r.write("<", tag)
+		if col < len(n.Align) && n.Align[col] != AlignNone {
+			r.write(r.class("align-" + n.Align[col]))
+		}
+		r.write(">", r.inline(text), "</", tag, ">")
+	}
+
+	r.write("<div", r.class("table"), ">\n<table>\n<thead>\n<tr>")
+	for col, text := range n.Header {
+		cell("th", col, text)
+	}
+	r.write("</tr>\n</thead>\n<tbody>\n")
+	for _, row := range n.Rows {
+		r.write("<tr>")
+		for col, text := range row {
+			cell("td", col, text)
+		}
+		r.write("</tr>\n")
+	}
+	r.write("</tbody>\n</table>\n</div>\n")
+}
+
+var (
+	headingIDInvalid = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+	inlineVariable   = regexp.MustCompile(`^<<(glossary:)?([\w.-]+(?: [\w.-]+)*)>>`)
+	inlineAutolink   = regexp.MustCompile(`^<((?:https?|mailto):[^\s<>]+)>`)
+	inlineTag        = regexp.MustCompile(`^</?[A-Za-z][A-Za-z0-9-]*(?:\s[^<>]*)?/?>`)
+	inlineComment    = regexp.MustCompile(`^<!--[\s\S]*?-->`)
+)
+
+// headingID derives the anchor id of a heading from its text
+func headingID(text string) string {
+	return strings.Trim(headingIDInvalid.ReplaceAllString(strings.ToLower(text), "-"), "-")
+}
+
+// inline renders the inline Markdown of the text: code spans, emphasis, links, images, inline
+// HTML and <<variable>> placeholders
+func (r *renderer) inline(text string) string {
+	out := strings.Builder{}
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		c := text[i]
+
+		switch {
+		case c == '\\' && i+1 < len(text) && strings.IndexByte("\\`*_{}[]()#+-.!<>|~", text[i+1]) >= 0:
+			out.WriteString(html.EscapeString(text[i+1 : i+2]))
+			i += 2
+			continue
+
+		case c == '\\' && i+1 < len(text) && text[i+1] == '\n':
+			out.WriteString("<br>\n")
+			i += 2
+			continue
+
+		case c == '`':
+			if code, n, ok := codeSpan(rest); ok {
+				out.WriteString("<code>" + html.EscapeString(code) + "</code>")
+				i += n
+				continue
+			}
+
+		case strings.HasPrefix(rest, "<<"):
+			if match := inlineVariable.FindStringSubmatch(rest); match != nil {
+				out.WriteString(r.variable(match[1] != "", match[2]))
+				i += len(match[0])
+				continue
+			}
+
+		case c == '<':
+			if match := inlineAutolink.FindStringSubmatch(rest); match != nil {
+				out.WriteString(`<a href="` + html.EscapeString(match[1]) + `">` + html.EscapeString(match[1]) + "</a>")
+				i += len(match[0])
+				continue
+			}
+			if match := inlineComment.FindString(rest); match != "" {
+				out.WriteString(match)
+				i += len(match)
+				continue
+			}
+			if match := inlineTag.FindString(rest); match != "" {
+				out.WriteString(match)
+				i += len(match)
+				continue
+			}
+
+		case c == '!' && strings.HasPrefix(rest, "!["):
+			if label, dest, title, n, ok := link(rest[1:]); ok {
+				out.WriteString(`<img src="` + html.EscapeString(dest) + `" alt="` + html.EscapeString(label) + `"`)
+				if title != "" {
+					out.WriteString(` title="` + html.EscapeString(title) + `"`)
+				}
+				out.WriteString(">")
+				i += n + 1
+				continue
+			}
+
+		case c == '[':
+			if label, dest, title, n, ok := link(rest); ok {
+				out.WriteString(`<a href="` + html.EscapeString(dest) + `"`)
+				if title != "" {
+					out.WriteString(` title="` + html.EscapeString(title) + `"`)
+				}
+				out.WriteString(">" + r.inline(label) + "</a>")
+				i += n
+				continue
+			}
+
+		case c == '*' || c == '_' || c == '~':
+			if html, n, ok := r.emphasis(rest); ok {
+				out.WriteString(html)
+				i += n
+				continue
+			}
+
+		case c == '\n':
+			// two trailing spaces make a hard line break
+			current := strings.TrimRight(out.String(), " ")
+			if len(out.String())-len(current) >= 2 {
+				out.Reset()
+				out.WriteString(current + "<br>\n")
+			} else {
+				out.WriteString("\n")
+			}
+			i++
+			continue
+		}
+
+		out.WriteString(html.EscapeString(text[i : i+1]))
+		i++
+	}
+
+	return out.String()
+}
+
+func (r *renderer) variable(glossary bool, name string) string {
+	if glossary {
+		return "<span" + r.class("glossary") + ">" + html.EscapeString(name) + "</span>"
+	}
+
+	if value, ok := r.opt.Variables[name]; ok {
+		return html.EscapeString(value)
+	}
+
+	return "<span" + r.class("variable") + ` data-variable="` + html.EscapeString(name) + `">` + html.EscapeString(name) + "</span>"
+}
+
+// emphasis renders a run of *, _ or ~ delimiters and its matching closing run
+func (r *renderer) emphasis(text string) (string, int, bool) {
+	c := text[0]
+	run := 0
+	for run < len(text) && text[run] == c && run < 3 {
+		run++
+	}
+
+	if c == '~' && run != 2 {
+		return "", 0, false
+	}
+
+	delimiter := text[:run]
+	if run >= len(text) || text[run] == ' ' || text[run] == '\n' {
+		return "", 0, false
+	}
+
+	end := -1
+	for j := run; j+run <= len(text); j++ {
+		if text[j] == '`' {
+			if _, n, ok := codeSpan(text[j:]); ok {
+				j += n - 1
+				continue
+			}
+		}
+		if text[j:j+run] == delimiter && text[j-1] != ' ' && (j+run == len(text) || text[j+run] != c) {
+			end = j
+			break
+		}
+	}
+
+	if end == -1 || end == run {
+		return "", 0, false
+	}
+
+	inner := r.inline(text[run:end])
+	switch {
+	case c == '~':
+		inner = "<del>" + inner + "</del>"
+	case run == 1:
+		inner = "<em>" + inner + "</em>"
+	case run == 2:
+		inner = "<strong>" + inner + "</strong>"
+	default:
+		inner = "<em><strong>" + inner + "</strong></em>"
+	}
+
+	return inner, end + run, true
+}
+
+// codeSpan parses a code span starting at the beginning of the text
+func codeSpan(text string) (string, int, bool) {
+	run := 0
+	for run < len(text) && text[run] == '`' {
+		run++
+	}
+
+	delimiter := text[:run]
+	for j := run; j < len(text); {
+		k := strings.Index(text[j:], delimiter)
+		if k == -1 {
+			return "", 0, false
+		}
+
+		start := j + k
+		end := start + run
+		if end < len(text) && text[end] == '`' {
+			// a longer run of backticks doesn't close the span
+			for end < len(text) && text[end] == '`' {
+				end++
+			}
+			j = end
+			continue
+		}
+
+		code := strings.ReplaceAll(text[run:start], "\n", " ")
+		if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' {
+			code = code[1 : len(code)-1]
+		}
+		return code, end, true
+	}
+
+	return "", 0, false
+}
+
+// link parses an inline [label](destination "title") link starting at the beginning of the text
+func link(text string) (string, string, string, int, bool) {
+	depth := 0
+	labelEnd := -1
+	for i := 0; i < len(text) && labelEnd == -1; i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				labelEnd = i
+			}
+		}
+	}
+
+	if labelEnd == -1 || labelEnd+1 >= len(text) || text[labelEnd+1] != '(' {
+		return "", "", "", 0, false
+	}
+
+	depth = 0
+	end := -1
+	for i := labelEnd + 1; i < len(text) && end == -1; i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				end = i
+			}
+		}
+	}
+
+	if end == -1 {
+		return "", "", "", 0, false
+	}
+
+	destination, title := splitLinkDestination(text[labelEnd+2 : end])
+	return text[1:labelEnd], destination, title, end + 1, true
+}
+
+// splitLinkDestination splits `url "title"` into the url and title
+func splitLinkDestination(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "<") {
+		if end := strings.Index(s, ">"); end != -1 {
+			return s[1:end], strings.Trim(strings.TrimSpace(s[end+1:]), `"'`)
+		}
+	}
+
+	fields := strings.SplitN(s, " ", 2)
+	if len(fields) == 1 {
+		return fields[0], ""
+	}
+	return fields[0], strings.Trim(strings.TrimSpace(fields[1]), `"'()`)
+}
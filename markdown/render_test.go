@@ -0,0 +1,95 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToHTML(t *testing.T) {
+	t.Run("renders blocks", func(t *testing.T) {
+		result := ToHTML("# Getting Started\n\nHello **world** and *you*.\n\n---\n\n- one\n- `two`\n\n3. three\n", RenderOptions{})
+
+		assert.Equal(t, `<h1 class="rdmd-heading" id="getting-started">Getting Started</h1>
+<p>Hello <strong>world</strong> and <em>you</em>.</p>
+<hr>
+<ul>
+<li>one</li>
+<li><code>two</code></li>
+</ul>
+<ol start="3">
+<li>three</li>
+</ol>
+`, result)
+	})
+
+	t.Run("renders callouts", func(t *testing.T) {
+		result := ToHTML("> 🚧 Beta\n>\n> Subject to [change](/docs/changes).", RenderOptions{})
+
+		assert.Equal(t, `<blockquote class="rdmd-callout rdmd-callout--warning">
+<h3 class="rdmd-callout__title"><span class="rdmd-callout__icon">🚧</span> Beta</h3>
+<p>Subject to <a href="/docs/changes">change</a>.</p>
+</blockquote>
+`, result)
+	})
+
+	t.Run("renders code tabs with a highlighter", func(t *testing.T) {
+		highlighter := HighlighterFunc(func(language string, code string) (string, bool) {
+			if language != "go" {
+				return "", false
+			}
+			return `<span class="kw">` + code + "</span>", true
+		})
+
+		result := ToHTML("```go Go\nfunc\n```\n```js\na < b\n```", RenderOptions{Highlighter: highlighter, ClassPrefix: "x-"})
+
+		assert.Equal(t, `<div class="x-code-tabs">
+<div class="x-code-tabs__toolbar"><button class="x-code-tabs__tab" data-tab="0">Go</button><button class="x-code-tabs__tab" data-tab="1">js</button></div>
+<pre class="x-code" data-lang="go"><code class="language-go"><span class="kw">func</span></code></pre>
+<pre class="x-code" data-lang="js"><code class="language-js">a &lt; b</code></pre>
+</div>
+`, result)
+	})
+
+	t.Run("renders tables and images", func(t *testing.T) {
+		result := ToHTML("| Name | Type |\n|:--|--|\n| `id` | string |\n\n[block:image]\n{\"images\": [{\"image\": [\"a.png\", \"A\"], \"caption\": \"An *image*\"}]}\n[/block]", RenderOptions{})
+
+		assert.Equal(t, `<div class="rdmd-table">
+<table>
+<thead>
+<tr><th class="rdmd-align-left">Name</th><th>Type</th></tr>
+</thead>
+<tbody>
+<tr><td class="rdmd-align-left"><code>id</code></td><td>string</td></tr>
+</tbody>
+</table>
+</div>
+<figure class="rdmd-image"><img src="a.png" alt="A" loading="lazy"><figcaption>An <em>image</em></figcaption></figure>
+`, result)
+	})
+
+	t.Run("substitutes variables", func(t *testing.T) {
+		result := ToHTML("Use <<apiKey>> as <<user>>, see <<glossary:token>>.", RenderOptions{
+			Variables: map[string]string{"apiKey": "<secret>"},
+		})
+
+		assert.Equal(t, `<p>Use &lt;secret&gt; as <span class="rdmd-variable" data-variable="user">user</span>, see <span class="rdmd-glossary">token</span>.</p>
+`, result)
+	})
+
+	t.Run("renders inline markup", func(t *testing.T) {
+		result := ToHTML("A ~~old~~ ![img](i.png \"T\") <b>raw</b> <https://readme.com> 2 < 3 \\*literal\\* `a*b*c`  \nnext", RenderOptions{})
+
+		assert.Equal(t, `<p>A <del>old</del> <img src="i.png" alt="img" title="T"> <b>raw</b> <a href="https://readme.com">https://readme.com</a> 2 &lt; 3 *literal* <code>a*b*c</code><br>
+next</p>
+`, result)
+	})
+
+	t.Run("renders recipes and embeds", func(t *testing.T) {
+		result := ToHTML("<Recipe slug=\"start\" title=\"Start & go\" emoji=\"🚀\" />\n\n[Video](https://youtu.be/x \"@embed\")", RenderOptions{})
+
+		assert.True(t, strings.HasPrefix(result, `<a class="rdmd-recipe" href="/recipes/start"><span class="rdmd-recipe__emoji">🚀</span><span class="rdmd-recipe__title">Start &amp; go</span></a>`))
+		assert.Contains(t, result, `<div class="rdmd-embed"><a href="https://youtu.be/x" target="_blank" rel="noopener">Video</a></div>`)
+	})
+}
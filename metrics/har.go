@@ -0,0 +1,243 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Redacted replaces the values of redacted headers and body fields
+const Redacted = "[REDACTED]"
+
+// Payload is a logged request, as ingested by ReadMe Developer Metrics
+type Payload struct {
+	ID              string `json:"_id"`
+	Group           Group  `json:"group"`
+	ClientIPAddress string `json:"clientIPAddress"`
+	Development     bool   `json:"development"`
+	Request         HAR    `json:"request"`
+}
+
+// Group identifies the API user that made a request
+type Group struct {
+	// ID is a unique identifier of the user, ex. an API key or account ID
+	ID    string `json:"id"`
+	Label string `json:"label,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// HAR is an HTTP Archive, see http://www.softwareishard.com/blog/har-12-spec/
+type HAR struct {
+	Log Log `json:"log"`
+}
+
+// Log is the root of an HTTP Archive
+type Log struct {
+	Creator Creator  `json:"creator"`
+	Entries []*Entry `json:"entries"`
+}
+
+// Creator is the application that created the log
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Comment string `json:"comment"`
+}
+
+// Entry is a request and response pair
+type Entry struct {
+	StartedDateTime time.Time `json:"startedDateTime"`
+
+	// Time is the duration of the request in milliseconds
+	Time     float64   `json:"time"`
+	Request  *Request  `json:"request"`
+	Response *Response `json:"response"`
+}
+
+// Request is the request of an entry
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	PostData    *PostData   `json:"postData,omitempty"`
+}
+
+// Response is the response of an entry
+type Response struct {
+	Status     int         `json:"status"`
+	StatusText string      `json:"statusText"`
+	Headers    []NameValue `json:"headers"`
+	Content    Content     `json:"content"`
+}
+
+// NameValue is a header or query string parameter
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// PostData is the body of a request
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// Content is the body of a response
+type Content struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// redactor removes sensitive headers and body fields from entries
+type redactor struct {
+	headers map[string]bool
+	fields  map[string]bool
+}
+
+func newRedactor(headers []string, fields []string) *redactor {
+	r := &redactor{headers: map[string]bool{}, fields: map[string]bool{}}
+	for _, header := range headers {
+		r.headers[strings.ToLower(header)] = true
+	}
+	for _, field := range fields {
+		r.fields[field] = true
+	}
+	return r
+}
+
+// headerValues returns the headers as name/value pairs sorted by name, with redacted values
+func (r *redactor) headerValues(header http.Header) []NameValue {
+	result := []NameValue{}
+	for name, values := range header {
+		for _, value := range values {
+			if r.headers[strings.ToLower(name)] {
+				value = Redacted
+			}
+			result = append(result, NameValue{Name: strings.ToLower(name), Value: value})
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// queryValues returns the query string as name/value pairs sorted by name, with redacted values
+func (r *redactor) queryValues(query url.Values) []NameValue {
+	result := []NameValue{}
+	for name, values := range query {
+		for _, value := range values {
+			if r.fields[name] {
+				value = Redacted
+			}
+			result = append(result, NameValue{Name: name, Value: value})
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// body redacts the fields of JSON and form encoded bodies. Other bodies are returned as is. When
+// fields are redacted, JSON and form bodies that can't be parsed, ex. because they were cut at
+// MaxBodySize, are replaced with Redacted so that unredacted fields are never sent.
+func (r *redactor) body(mimeType string, text string) string {
+	if len(r.fields) == 0 || text == "" {
+		return text
+	}
+
+	switch {
+	case strings.Contains(mimeType, "json"):
+		var value interface{}
+		if err := json.Unmarshal([]byte(text), &value); err != nil {
+			return Redacted
+		}
+
+		data, err := json.Marshal(r.redactValue(value))
+		if err != nil {
+			return Redacted
+		}
+		return string(data)
+
+	case strings.HasPrefix(mimeType, "application/x-www-form-urlencoded"):
+		form, err := url.ParseQuery(text)
+		if err != nil {
+			return Redacted
+		}
+
+		for name := range form {
+			if r.fields[name] {
+				for i := range form[name] {
+					form[name][i] = Redacted
+				}
+			}
+		}
+		return form.Encode()
+	}
+
+	return text
+}
+
+// redactValue replaces the fields of objects at any depth
+func (r *redactor) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, field := range v {
+			if r.fields[name] {
+				v[name] = Redacted
+			} else {
+				v[name] = r.redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.redactValue(item)
+		}
+	}
+	return value
+}
+
+// requestURL returns the absolute URL of a server request, with redacted query string values
+func (rd *redactor) requestURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("x-forwarded-proto"); proto != "" {
+		scheme = strings.TrimSpace(strings.Split(proto, ",")[0])
+	}
+
+	u := scheme + "://" + r.Host + r.URL.EscapedPath()
+	if r.URL.RawQuery == "" {
+		return u
+	}
+
+	query := r.URL.Query()
+	for name, values := range query {
+		if rd.fields[name] {
+			for i := range values {
+				values[i] = Redacted
+			}
+		}
+	}
+	return u + "?" + query.Encode()
+}
+
+// clientIP returns the address of the client of a request, without the port
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
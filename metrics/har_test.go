@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactor(t *testing.T) {
+	r := newRedactor([]string{"Authorization"}, []string{"password", "token"})
+
+	t.Run("redacts headers case insensitively", func(t *testing.T) {
+		values := r.headerValues(http.Header{"Authorization": {"Basic abc"}, "Accept": {"text/html", "application/json"}})
+
+		assert.Equal(t, []NameValue{
+			{Name: "accept", Value: "text/html"},
+			{Name: "accept", Value: "application/json"},
+			{Name: "authorization", Value: Redacted},
+		}, values)
+	})
+
+	t.Run("redacts JSON fields at any depth", func(t *testing.T) {
+		body := r.body("application/json; charset=utf-8", `[{"user":{"password":"a","name":"b"}},{"token":{"nested":true}}]`)
+
+		assert.Equal(t, `[{"user":{"name":"b","password":"[REDACTED]"}},{"token":"[REDACTED]"}]`, body)
+	})
+
+	t.Run("redacts form fields", func(t *testing.T) {
+		assert.Equal(t, "password=%5BREDACTED%5D&user=ada", r.body("application/x-www-form-urlencoded", "user=ada&password=hunter2"))
+	})
+
+	t.Run("leaves other bodies alone", func(t *testing.T) {
+		assert.Equal(t, "password=hunter2", r.body("text/plain", "password=hunter2"))
+	})
+
+	t.Run("replaces bodies that can't be parsed", func(t *testing.T) {
+		assert.Equal(t, Redacted, r.body("application/json", `{"password":"hunt`))
+		assert.Equal(t, Redacted, r.body("application/x-www-form-urlencoded", "password=%zz"))
+	})
+
+	t.Run("leaves bodies alone without fields to redact", func(t *testing.T) {
+		assert.Equal(t, `{"password":`, newRedactor(nil, nil).body("application/json", `{"password":`))
+	})
+}
+
+func TestRequestURL(t *testing.T) {
+	r := newRedactor(nil, []string{"password"})
+
+	request := httptest.NewRequest("GET", "/pets?limit=2", nil)
+	assert.Equal(t, "http://example.com/pets?limit=2", r.requestURL(request))
+
+	request.TLS = &tls.ConnectionState{}
+	assert.Equal(t, "https://example.com/pets?limit=2", r.requestURL(request))
+
+	request.TLS = nil
+	request.Header.Set("x-forwarded-proto", "https, http")
+	assert.Equal(t, "https://example.com/pets?limit=2", r.requestURL(request))
+
+	request = httptest.NewRequest("GET", "/pets?password=hunter2&limit=2", nil)
+	assert.Equal(t, "http://example.com/pets?limit=2&password=%5BREDACTED%5D", r.requestURL(request))
+
+	request = httptest.NewRequest("GET", "/pets", nil)
+	assert.Equal(t, "http://example.com/pets", r.requestURL(request))
+}
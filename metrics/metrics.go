@@ -0,0 +1,401 @@
+// Package metrics logs the requests of net/http servers to ReadMe Developer Metrics. The middleware
+// records request and response pairs as HAR entries, redacts sensitive headers and body fields, and
+// sends the entries in batches from a background goroutine so that requests are not slowed down.
+//
+//	m, err := metrics.New(metrics.Options{
+//		ApiKey: os.Getenv("README_API_KEY"),
+//		Identify: func(r *http.Request) (metrics.Group, bool) {
+//			user := auth.User(r)
+//			return metrics.Group{ID: user.ID, Email: user.Email}, user != nil
+//		},
+//	})
+//	server := &http.Server{Handler: m.Handler(mux)}
+//	...
+//	m.Shutdown(ctx)
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultEndpoint is the address of the ReadMe Developer Metrics API
+const DefaultEndpoint = "https://metrics.readme.io/request"
+
+// ErrClosed is returned by Shutdown when the middleware was already shut down
+var ErrClosed = errors.New("metrics middleware is shut down")
+
+// Options are the options of the metrics middleware
+type Options struct {
+	// ApiKey is the ReadMe API key of the project
+	ApiKey string
+
+	// Endpoint is the address entries are sent to. Defaults to DefaultEndpoint.
+	Endpoint string
+
+	// Identify returns the API user that made a request. Requests are not logged when it returns
+	// false, ex. for unauthenticated requests.
+	Identify func(r *http.Request) (Group, bool)
+
+	// RedactHeaders are headers whose values are replaced with Redacted. Defaults to Authorization,
+	// Cookie and Set-Cookie.
+	RedactHeaders []string
+
+	// RedactFields are query string parameters, form fields and JSON object fields at any depth
+	// whose values are replaced with Redacted, ex. "password"
+	RedactFields []string
+
+	// BufferSize is the number of entries waiting to be sent that are kept in memory. Defaults to 1000.
+	BufferSize int
+
+	// BatchSize is the number of entries sent in a request. Defaults to 10.
+	BatchSize int
+
+	// FlushInterval is the longest time an entry waits before being sent. Defaults to 5 seconds.
+	FlushInterval time.Duration
+
+	// MaxBodySize is the number of bytes of request and response bodies that are logged. Defaults to
+	// 64 KiB.
+	MaxBodySize int
+
+	// Block makes requests wait for room in a full buffer, applying backpressure to the server.
+	// By default entries are dropped when the buffer is full, see Middleware.Dropped.
+	Block bool
+
+	// Development marks the entries as coming from a development environment
+	Development bool
+
+	// HttpClient sends the entries. Defaults to a client with a 10 second timeout.
+	HttpClient *http.Client
+
+	// OnError is called when a batch could not be sent
+	OnError func(err error)
+}
+
+// Middleware logs requests to ReadMe Developer Metrics
+type Middleware struct {
+	opt      Options
+	redactor *redactor
+
+	queue  chan *Payload
+	done   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.RWMutex
+	closed  bool
+	dropped int64
+}
+
+// New returns a middleware and starts sending entries in the background. Call Shutdown to send the
+// remaining entries before exiting.
+func New(opt Options) (*Middleware, error) {
+	if opt.ApiKey == "" {
+		return nil, errors.New("an API key is required")
+	}
+
+	if opt.Identify == nil {
+		return nil, errors.New("an Identify function is required")
+	}
+
+	if opt.Endpoint == "" {
+		opt.Endpoint = DefaultEndpoint
+	}
+	if opt.RedactHeaders == nil {
+		opt.RedactHeaders = []string{"authorization", "cookie", "set-cookie"}
+	}
+	if opt.BufferSize <= 0 {
+		opt.BufferSize = 1000
+	}
+	if opt.BatchSize <= 0 {
+		opt.BatchSize = 10
+	}
+	if opt.FlushInterval <= 0 {
+		opt.FlushInterval = 5 * time.Second
+	}
+	if opt.MaxBodySize <= 0 {
+		opt.MaxBodySize = 64 << 10
+	}
+	if opt.HttpClient == nil {
+		opt.HttpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Middleware{
+		opt:      opt,
+		redactor: newRedactor(opt.RedactHeaders, opt.RedactFields),
+		queue:    make(chan *Payload, opt.BufferSize),
+		done:     make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+
+	go m.run()
+	return m, nil
+}
+
+// Dropped returns the number of entries that were dropped because the buffer was full, the
+// middleware was shut down, or Shutdown gave up waiting for them to be sent
+func (m *Middleware) Dropped() int64 {
+	return atomic.LoadInt64(&m.dropped)
+}
+
+// Handler wraps a handler to log its requests
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+
+		var requestBody []byte
+		if r.Body != nil && r.Body != http.NoBody {
+			requestBody, _ = ioutil.ReadAll(io.LimitReader(r.Body, int64(m.opt.MaxBodySize)))
+			r.Body = &replayBody{Reader: io.MultiReader(bytes.NewReader(requestBody), r.Body), Closer: r.Body}
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK, limit: m.opt.MaxBodySize}
+		next.ServeHTTP(recorder, r)
+
+		group, ok := m.opt.Identify(r)
+		if !ok {
+			return
+		}
+
+		m.enqueue(r.Context(), m.payload(r, requestBody, recorder, group, started))
+	})
+}
+
+// payload builds the logged entry of a request
+func (m *Middleware) payload(r *http.Request, requestBody []byte, recorder *responseRecorder, group Group, started time.Time) *Payload {
+	request := &Request{
+		Method:      r.Method,
+		URL:         m.redactor.requestURL(r),
+		HTTPVersion: r.Proto,
+		Headers:     m.redactor.headerValues(r.Header),
+		QueryString: m.redactor.queryValues(r.URL.Query()),
+	}
+
+	if len(requestBody) > 0 {
+		mimeType := r.Header.Get("content-type")
+		request.PostData = &PostData{MimeType: mimeType, Text: m.redactor.body(mimeType, string(requestBody))}
+	}
+
+	mimeType := recorder.Header().Get("content-type")
+	response := &Response{
+		Status:     recorder.status,
+		StatusText: http.StatusText(recorder.status),
+		Headers:    m.redactor.headerValues(recorder.Header()),
+		Content: Content{
+			Size:     recorder.size,
+			MimeType: mimeType,
+			Text:     m.redactor.body(mimeType, recorder.body.String()),
+		},
+	}
+
+	return &Payload{
+		ID:              newID(),
+		Group:           group,
+		ClientIPAddress: clientIP(r),
+		Development:     m.opt.Development,
+		Request: HAR{Log: Log{
+			Creator: Creator{Name: "readme-metrics (go)", Version: "go-readme", Comment: runtime.GOARCH + "-" + runtime.GOOS + "/" + runtime.Version()},
+			Entries: []*Entry{{
+				StartedDateTime: started.UTC(),
+				Time:            float64(time.Since(started)) / float64(time.Millisecond),
+				Request:         request,
+				Response:        response,
+			}},
+		}},
+	}
+}
+
+// enqueue adds a payload to the buffer, waiting for room when Block is set
+func (m *Middleware) enqueue(ctx context.Context, payload *Payload) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.closed {
+		atomic.AddInt64(&m.dropped, 1)
+		return
+	}
+
+	if m.opt.Block {
+		select {
+		case m.queue <- payload:
+		case <-ctx.Done():
+			atomic.AddInt64(&m.dropped, 1)
+		}
+		return
+	}
+
+	select {
+	case m.queue <- payload:
+	default:
+		atomic.AddInt64(&m.dropped, 1)
+	}
+}
+
+// run sends the entries of the buffer in batches until the buffer is closed
+func (m *Middleware) run() {
+	defer close(m.done)
+
+	ticker := time.NewTicker(m.opt.FlushInterval)
+	defer ticker.Stop()
+
+	batch := []*Payload{}
+	flush := func() {
+		if len(batch) > 0 {
+			m.send(batch)
+			batch = []*Payload{}
+		}
+	}
+
+	for {
+		select {
+		case payload, ok := <-m.queue:
+			if !ok {
+				flush()
+				return
+			}
+
+			batch = append(batch, payload)
+			if len(batch) >= m.opt.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// send posts a batch to the endpoint. Batches that fail because Shutdown gave up waiting are
+// counted as dropped.
+func (m *Middleware) send(batch []*Payload) {
+	err := m.post(batch)
+	if err == nil {
+		return
+	}
+
+	if m.ctx.Err() != nil {
+		atomic.AddInt64(&m.dropped, int64(len(batch)))
+	}
+	if m.opt.OnError != nil {
+		m.opt.OnError(err)
+	}
+}
+
+func (m *Middleware) post(batch []*Payload) error {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("could not marshal %d entries: %w", len(batch), err)
+	}
+
+	request, err := http.NewRequestWithContext(m.ctx, http.MethodPost, m.opt.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("could not create request: %w", err)
+	}
+
+	request.SetBasicAuth(m.opt.ApiKey, "")
+	request.Header.Set("content-type", "application/json")
+	request.Header.Set("user-agent", "go-readme-metrics")
+
+	response, err := m.opt.HttpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("could not send %d entries: %w", len(batch), err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 400 {
+		message, _ := ioutil.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("could not send %d entries: server error (%v): %s", len(batch), response.StatusCode, message)
+	}
+
+	return nil
+}
+
+// Shutdown stops logging requests and sends the entries of the buffer. If the context expires first,
+// the pending request is canceled and the remaining entries are dropped.
+func (m *Middleware) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return ErrClosed
+	}
+	m.closed = true
+	close(m.queue)
+	m.mu.Unlock()
+
+	select {
+	case <-m.done:
+		m.cancel()
+		return nil
+	case <-ctx.Done():
+		m.cancel()
+		<-m.done
+		return ctx.Err()
+	}
+}
+
+// responseRecorder captures the status and the beginning of the body of a response
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	size        int
+	limit       int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	if room := r.limit - r.body.Len(); room > 0 {
+		if room > len(p) {
+			room = len(p)
+		}
+		r.body.Write(p[:room])
+	}
+
+	n, err := r.ResponseWriter.Write(p)
+	r.size += n
+	return n, err
+}
+
+// Flush supports streaming handlers
+func (r *responseRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// replayBody is a request body whose beginning was read for logging
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+// newID returns a random version 4 UUID
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,299 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// receiver is a stub metrics endpoint that records the batches it receives
+type receiver struct {
+	mu      sync.Mutex
+	batches [][]*Payload
+	status  int
+	release chan struct{}
+}
+
+func newReceiver(t *testing.T) (*receiver, *httptest.Server) {
+	r := &receiver{status: http.StatusAccepted}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		user, _, _ := req.BasicAuth()
+		assert.Equal(t, "testKey", user)
+		assert.Equal(t, "application/json", req.Header.Get("content-type"))
+
+		if r.release != nil {
+			select {
+			case <-r.release:
+			case <-req.Context().Done():
+				return
+			}
+		}
+
+		batch := []*Payload{}
+		assert.Nil(t, json.NewDecoder(req.Body).Decode(&batch))
+
+		r.mu.Lock()
+		r.batches = append(r.batches, batch)
+		status := r.status
+		r.mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return r, server
+}
+
+func (r *receiver) entries() []*Payload {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := []*Payload{}
+	for _, batch := range r.batches {
+		result = append(result, batch...)
+	}
+	return result
+}
+
+func (r *receiver) batchSizes() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := []int{}
+	for _, batch := range r.batches {
+		result = append(result, len(batch))
+	}
+	return result
+}
+
+func identifyByHeader(r *http.Request) (Group, bool) {
+	id := r.Header.Get("x-user")
+	return Group{ID: id, Email: id + "@example.com"}, id != ""
+}
+
+var echo = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	w.Header().Set("content-type", "application/json")
+	w.Header().Set("set-cookie", "session=abc")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(body)
+})
+
+func serve(handler http.Handler, method string, target string, body string, header http.Header) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	for name, values := range header {
+		request.Header[name] = values
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Options{Identify: identifyByHeader})
+	assert.EqualError(t, err, "an API key is required")
+
+	_, err = New(Options{ApiKey: "testKey"})
+	assert.EqualError(t, err, "an Identify function is required")
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("logs requests as HAR entries", func(t *testing.T) {
+		r, server := newReceiver(t)
+		m, err := New(Options{ApiKey: "testKey", Endpoint: server.URL, Identify: identifyByHeader, RedactFields: []string{"password"}})
+		assert.Nil(t, err)
+
+		header := http.Header{
+			"X-User":        {"ada"},
+			"Authorization": {"Bearer secret"},
+			"Content-Type":  {"application/json"},
+		}
+		response := serve(m.Handler(echo), "POST", "/pets?limit=2&password=hunter2", `{"name":"Rex","owner":{"password":"hunter2"}}`, header)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.Equal(t, `{"name":"Rex","owner":{"password":"hunter2"}}`, response.Body.String(), "the handler sees the unredacted body")
+		assert.Nil(t, m.Shutdown(context.Background()))
+
+		entries := r.entries()
+		assert.Len(t, entries, 1)
+
+		payload := entries[0]
+		assert.Len(t, payload.ID, 36)
+		assert.Equal(t, Group{ID: "ada", Email: "ada@example.com"}, payload.Group)
+		assert.Equal(t, "192.0.2.1", payload.ClientIPAddress)
+		assert.Equal(t, "readme-metrics (go)", payload.Request.Log.Creator.Name)
+
+		entry := payload.Request.Log.Entries[0]
+		assert.Equal(t, &Request{
+			Method:      "POST",
+			URL:         "http://example.com/pets?limit=2&password=%5BREDACTED%5D",
+			HTTPVersion: "HTTP/1.1",
+			Headers: []NameValue{
+				{Name: "authorization", Value: Redacted},
+				{Name: "content-type", Value: "application/json"},
+				{Name: "x-user", Value: "ada"},
+			},
+			QueryString: []NameValue{
+				{Name: "limit", Value: "2"},
+				{Name: "password", Value: Redacted},
+			},
+			PostData: &PostData{MimeType: "application/json", Text: `{"name":"Rex","owner":{"password":"[REDACTED]"}}`},
+		}, entry.Request)
+		assert.Equal(t, &Response{
+			Status:     http.StatusCreated,
+			StatusText: "Created",
+			Headers: []NameValue{
+				{Name: "content-type", Value: "application/json"},
+				{Name: "set-cookie", Value: Redacted},
+			},
+			Content: Content{Size: 45, MimeType: "application/json", Text: `{"name":"Rex","owner":{"password":"[REDACTED]"}}`},
+		}, entry.Response)
+		assert.False(t, entry.StartedDateTime.IsZero())
+	})
+
+	t.Run("redacts bodies larger than MaxBodySize", func(t *testing.T) {
+		r, server := newReceiver(t)
+		m, _ := New(Options{ApiKey: "testKey", Endpoint: server.URL, Identify: identifyByHeader, RedactFields: []string{"password"}, MaxBodySize: 32})
+
+		body := `{"password":"hunter2","notes":"` + strings.Repeat("x", 64) + `"}`
+		response := serve(m.Handler(echo), "POST", "/pets", body, http.Header{"X-User": {"ada"}, "Content-Type": {"application/json"}})
+		assert.Equal(t, body, response.Body.String(), "the handler sees the whole body")
+		assert.Nil(t, m.Shutdown(context.Background()))
+
+		entry := r.entries()[0].Request.Log.Entries[0]
+		assert.Equal(t, Redacted, entry.Request.PostData.Text)
+		assert.Equal(t, Redacted, entry.Response.Content.Text)
+		assert.Equal(t, len(body), entry.Response.Content.Size)
+	})
+
+	t.Run("skips unidentified requests", func(t *testing.T) {
+		r, server := newReceiver(t)
+		m, _ := New(Options{ApiKey: "testKey", Endpoint: server.URL, Identify: identifyByHeader})
+
+		serve(m.Handler(echo), "GET", "/pets", "", nil)
+		assert.Nil(t, m.Shutdown(context.Background()))
+
+		assert.Empty(t, r.entries())
+	})
+
+	t.Run("sends full batches", func(t *testing.T) {
+		r, server := newReceiver(t)
+		m, _ := New(Options{ApiKey: "testKey", Endpoint: server.URL, Identify: identifyByHeader, BatchSize: 2, FlushInterval: time.Hour})
+
+		for i := 0; i < 5; i++ {
+			serve(m.Handler(echo), "GET", "/pets", "", http.Header{"X-User": {"ada"}})
+		}
+		assert.Nil(t, m.Shutdown(context.Background()))
+
+		assert.Equal(t, []int{2, 2, 1}, r.batchSizes())
+	})
+
+	t.Run("sends partial batches after the flush interval", func(t *testing.T) {
+		r, server := newReceiver(t)
+		m, _ := New(Options{ApiKey: "testKey", Endpoint: server.URL, Identify: identifyByHeader, FlushInterval: 10 * time.Millisecond})
+		defer m.Shutdown(context.Background())
+
+		serve(m.Handler(echo), "GET", "/pets", "", http.Header{"X-User": {"ada"}})
+
+		assert.Eventually(t, func() bool {
+			return len(r.entries()) == 1
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("drops entries when the buffer is full", func(t *testing.T) {
+		r, server := newReceiver(t)
+		r.release = make(chan struct{})
+		m, _ := New(Options{ApiKey: "testKey", Endpoint: server.URL, Identify: identifyByHeader, BatchSize: 1, BufferSize: 2, FlushInterval: time.Hour})
+
+		handler := m.Handler(echo)
+		serve(handler, "GET", "/pets", "", http.Header{"X-User": {"ada"}})
+
+		// The first entry is being sent, the next two fill the buffer
+		assert.Eventually(t, func() bool {
+			return len(m.queue) == 0
+		}, time.Second, time.Millisecond)
+		for i := 0; i < 4; i++ {
+			serve(handler, "GET", "/pets", "", http.Header{"X-User": {"ada"}})
+		}
+
+		assert.Equal(t, int64(2), m.Dropped())
+		close(r.release)
+		assert.Nil(t, m.Shutdown(context.Background()))
+		assert.Len(t, r.entries(), 3)
+	})
+
+	t.Run("blocks requests when the buffer is full", func(t *testing.T) {
+		r, server := newReceiver(t)
+		r.release = make(chan struct{})
+		m, _ := New(Options{ApiKey: "testKey", Endpoint: server.URL, Identify: identifyByHeader, BatchSize: 1, BufferSize: 1, FlushInterval: time.Hour, Block: true})
+
+		handler := m.Handler(echo)
+		serve(handler, "GET", "/pets", "", http.Header{"X-User": {"ada"}})
+		assert.Eventually(t, func() bool {
+			return len(m.queue) == 0
+		}, time.Second, time.Millisecond)
+		serve(handler, "GET", "/pets", "", http.Header{"X-User": {"ada"}})
+
+		served := make(chan struct{})
+		go func() {
+			serve(handler, "GET", "/pets", "", http.Header{"X-User": {"ada"}})
+			close(served)
+		}()
+
+		select {
+		case <-served:
+			t.Fatal("request was not blocked by the full buffer")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		close(r.release)
+		<-served
+		assert.Nil(t, m.Shutdown(context.Background()))
+		assert.Len(t, r.entries(), 3)
+		assert.Equal(t, int64(0), m.Dropped())
+	})
+
+	t.Run("reports send errors", func(t *testing.T) {
+		r, server := newReceiver(t)
+		r.status = http.StatusUnauthorized
+
+		errs := []error{}
+		m, _ := New(Options{ApiKey: "testKey", Endpoint: server.URL, Identify: identifyByHeader, OnError: func(err error) {
+			errs = append(errs, err)
+		}})
+
+		serve(m.Handler(echo), "GET", "/pets", "", http.Header{"X-User": {"ada"}})
+		assert.Nil(t, m.Shutdown(context.Background()))
+
+		assert.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "could not send 1 entries: server error (401)")
+	})
+
+	t.Run("stops waiting when the shutdown context expires", func(t *testing.T) {
+		r, server := newReceiver(t)
+		r.release = make(chan struct{})
+		defer close(r.release)
+
+		m, _ := New(Options{ApiKey: "testKey", Endpoint: server.URL, Identify: identifyByHeader, OnError: func(error) {}})
+		serve(m.Handler(echo), "GET", "/pets", "", http.Header{"X-User": {"ada"}})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, m.Shutdown(ctx), context.DeadlineExceeded)
+		assert.ErrorIs(t, m.Shutdown(context.Background()), ErrClosed)
+
+		// The entry that was being sent and the entry logged after the shutdown
+		serve(m.Handler(echo), "GET", "/pets", "", http.Header{"X-User": {"ada"}})
+		assert.Equal(t, int64(2), m.Dropped())
+	})
+}
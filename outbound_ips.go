@@ -0,0 +1,22 @@
+package readme
+
+import "context"
+
+// OutboundIP is an address readme sends requests, such as webhooks, from
+type OutboundIP struct {
+	IPAddress string `json:"ipAddress"`
+}
+
+// OutboundIPs allows fetching the addresses readme sends requests from, ex. to allow them in a firewall
+type OutboundIPs interface {
+	List(ctx context.Context) ([]*OutboundIP, error)
+}
+
+type outboundIPs struct {
+	client *Client
+}
+
+// List the outbound IP addresses
+func (o *outboundIPs) List(ctx context.Context) ([]*OutboundIP, error) {
+	return getJSON[[]*OutboundIP](ctx, o.client, "outbound-ips", nil)
+}
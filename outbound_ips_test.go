@@ -0,0 +1,21 @@
+package readme
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboundIPs_List(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/outbound-ips", r.URL.Path)
+		writeJSON(w, []map[string]string{{"ipAddress": "192.0.2.10"}, {"ipAddress": "192.0.2.11"}})
+	}))
+
+	ips, err := client.OutboundIPs.List(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, []*OutboundIP{{IPAddress: "192.0.2.10"}, {IPAddress: "192.0.2.11"}}, ips)
+}
@@ -0,0 +1,276 @@
+package readme
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultProfile is the name of the profile used when no profile is specified and the config file
+// doesn't set one
+const DefaultProfile = "default"
+
+// ConfigFile is a config file of named profiles, ex.
+//
+//	default: petstore
+//	profiles:
+//	  petstore:
+//	    api_key: rdme_xxx
+//	    version: "2.0"
+//	  billing:
+//	    key_command: pass show readme/billing
+//	    timeout: 30s
+type ConfigFile struct {
+	// Default is the name of the profile used when no profile is specified
+	Default string `yaml:"default"`
+
+	Profiles map[string]*Profile `yaml:"profiles"`
+}
+
+// Profile holds the settings of a client for one project
+type Profile struct {
+	// Name is the name of the profile in the config file
+	Name string `yaml:"-"`
+
+	// Address is the host of the readme API. Defaults to DefaultAddress.
+	Address string `yaml:"address,omitempty"`
+
+	// ApiKey authenticates requests. Files containing keys must not be readable by other users.
+	ApiKey string `yaml:"api_key,omitempty"`
+
+	// KeyCommand is a shell command that prints the API key, ex. "pass show readme/petstore". It is
+	// used when ApiKey is empty.
+	KeyCommand string `yaml:"key_command,omitempty"`
+
+	// Version is the project version (semver, ex. "1.0") every request is scoped to
+	Version string `yaml:"version,omitempty"`
+
+	// Headers are sent with every request
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// Timeout limits the time of each request, ex. "30s". Zero means no timeout.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// DefaultConfigFilePath returns the path of the config file: $README_CONFIG_FILE, or
+// go-readme/config.yaml in the user's config directory, ex. ~/.config/go-readme/config.yaml
+func DefaultConfigFilePath() (string, error) {
+	if path := os.Getenv("README_CONFIG_FILE"); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("could not find config directory: %w", err)
+	}
+
+	return filepath.Join(dir, "go-readme", "config.yaml"), nil
+}
+
+// LoadConfigFile reads a config file. Files that contain API keys are refused when other users can
+// read or write them.
+func LoadConfigFile(path string) (*ConfigFile, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read config file: %w", err)
+	}
+
+	result := &ConfigFile{}
+	if err := yaml.Unmarshal(data, result); err != nil {
+		return nil, fmt.Errorf("could not parse config file %s: %w", path, err)
+	}
+
+	for name, profile := range result.Profiles {
+		if profile == nil {
+			profile = &Profile{}
+			result.Profiles[name] = profile
+		}
+		profile.Name = name
+	}
+
+	if result.hasKeys() && runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not stat config file: %w", err)
+		}
+
+		if info.Mode().Perm()&0007 != 0 {
+			return nil, fmt.Errorf("config file %s contains API keys and is accessible by other users (mode %v), run chmod 600 on it", path, info.Mode().Perm())
+		}
+	}
+
+	return result, nil
+}
+
+func (c *ConfigFile) hasKeys() bool {
+	for _, profile := range c.Profiles {
+		if profile.ApiKey != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Profile returns the named profile, or the default profile when the name is empty
+func (c *ConfigFile) Profile(name string) (*Profile, error) {
+	if name == "" {
+		name = c.Default
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok {
+		names := []string{}
+		for name := range c.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		if len(names) == 0 {
+			return nil, fmt.Errorf("profile %q does not exist", name)
+		}
+		return nil, fmt.Errorf("profile %q does not exist, the config file has: %s", name, strings.Join(names, ", "))
+	}
+
+	result := *profile
+	return &result, nil
+}
+
+// LoadProfile returns a profile of the config file, see DefaultConfigFilePath. Settings are taken, in
+// order of precedence, from:
+//
+//  1. the environment: README_API_KEY and README_VERSION
+//  2. the profile: the name argument, $README_PROFILE, the default of the config file or "default"
+//  3. the defaults of DefaultConfig
+//
+// A missing config file is only an error when a profile is named by the argument or $README_PROFILE.
+func LoadProfile(name string) (*Profile, error) {
+	if name == "" {
+		name = os.Getenv("README_PROFILE")
+	}
+
+	path, err := DefaultConfigFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := LoadConfigFile(path)
+	if errors.Is(err, os.ErrNotExist) && name == "" {
+		file = &ConfigFile{}
+	} else if err != nil {
+		return nil, err
+	}
+
+	profile, err := file.Profile(name)
+	if err != nil {
+		// Without a named profile the environment alone is enough
+		if name != "" || file.Default != "" {
+			return nil, err
+		}
+		profile = &Profile{}
+	}
+
+	if apiKey := os.Getenv("README_API_KEY"); apiKey != "" {
+		profile.ApiKey = apiKey
+		profile.KeyCommand = ""
+	}
+
+	if version := os.Getenv("README_VERSION"); version != "" {
+		profile.Version = version
+	}
+
+	return profile, nil
+}
+
+// NewClientFromProfile creates a client using a profile of the config file, see LoadProfile. An
+// empty name selects the default profile.
+func NewClientFromProfile(name string) (*Client, error) {
+	profile, err := LoadProfile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return profile.NewClient()
+}
+
+// Config returns the client config of the profile, running the key command if there is no API key
+func (p *Profile) Config() (*Config, error) {
+	config := &Config{
+		Address: p.Address,
+		ApiKey:  p.ApiKey,
+		Headers: make(http.Header),
+	}
+
+	if config.ApiKey == "" && p.KeyCommand != "" {
+		apiKey, err := runKeyCommand(p.KeyCommand)
+		if err != nil {
+			return nil, err
+		}
+		config.ApiKey = apiKey
+	}
+
+	for name, value := range p.Headers {
+		config.Headers.Set(name, value)
+	}
+
+	if p.Timeout > 0 {
+		config.HttpClient = &http.Client{Timeout: p.Timeout}
+	}
+
+	return config, nil
+}
+
+// NewClient creates a client using the settings of the profile
+func (p *Profile) NewClient() (*Client, error) {
+	config, err := p.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Version != "" {
+		client = client.WithVersion(p.Version)
+	}
+
+	return client, nil
+}
+
+// runKeyCommand runs a key command with the shell and returns its trimmed output
+func runKeyCommand(command string) (string, error) {
+	cmd := exec.Command("sh", "-c", command)
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	}
+
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("could not run key command: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	apiKey := strings.TrimSpace(string(output))
+	if apiKey == "" {
+		return "", errors.New("key command printed an empty API key")
+	}
+
+	return apiKey, nil
+}
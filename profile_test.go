@@ -0,0 +1,191 @@
+package readme
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeConfigFile writes a config file to a temporary directory and points README_CONFIG_FILE at it
+func writeConfigFile(t *testing.T, content string, mode os.FileMode) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(content), mode))
+	assert.Nil(t, os.Chmod(path, mode))
+
+	t.Setenv("README_CONFIG_FILE", path)
+	for _, name := range []string{"README_API_KEY", "README_PROFILE", "README_VERSION"} {
+		t.Setenv(name, "")
+	}
+
+	return path
+}
+
+const testConfigFile = `
+default: petstore
+profiles:
+  petstore:
+    api_key: petKey
+    version: "2.0"
+    headers:
+      x-team: pets
+    timeout: 30s
+  billing:
+    address: https://readme.example.com
+    key_command: echo billingKey
+`
+
+func TestLoadConfigFile(t *testing.T) {
+	t.Run("reads profiles", func(t *testing.T) {
+		path := writeConfigFile(t, testConfigFile, 0600)
+
+		file, err := LoadConfigFile(path)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "petstore", file.Default)
+		assert.Equal(t, &Profile{
+			Name:    "petstore",
+			ApiKey:  "petKey",
+			Version: "2.0",
+			Headers: map[string]string{"x-team": "pets"},
+			Timeout: 30 * time.Second,
+		}, file.Profiles["petstore"])
+		assert.Equal(t, "echo billingKey", file.Profiles["billing"].KeyCommand)
+	})
+
+	t.Run("refuses files with keys that other users can read", func(t *testing.T) {
+		path := writeConfigFile(t, testConfigFile, 0644)
+
+		_, err := LoadConfigFile(path)
+
+		assert.EqualError(t, err, "config file "+path+" contains API keys and is accessible by other users (mode -rw-r--r--), run chmod 600 on it")
+	})
+
+	t.Run("allows readable files without keys", func(t *testing.T) {
+		path := writeConfigFile(t, "profiles:\n  billing:\n    key_command: echo billingKey\n", 0644)
+
+		_, err := LoadConfigFile(path)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("reports invalid files", func(t *testing.T) {
+		path := writeConfigFile(t, "profiles: [", 0600)
+
+		_, err := LoadConfigFile(path)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "could not parse config file")
+	})
+}
+
+func TestLoadProfile(t *testing.T) {
+	t.Run("uses the default of the config file", func(t *testing.T) {
+		writeConfigFile(t, testConfigFile, 0600)
+
+		profile, err := LoadProfile("")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "petstore", profile.Name)
+	})
+
+	t.Run("uses README_PROFILE", func(t *testing.T) {
+		writeConfigFile(t, testConfigFile, 0600)
+		t.Setenv("README_PROFILE", "billing")
+
+		profile, err := LoadProfile("")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "billing", profile.Name)
+
+		profile, err = LoadProfile("petstore")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "petstore", profile.Name)
+	})
+
+	t.Run("overrides the profile with the environment", func(t *testing.T) {
+		writeConfigFile(t, testConfigFile, 0600)
+		t.Setenv("README_API_KEY", "envKey")
+		t.Setenv("README_VERSION", "3.0")
+
+		profile, err := LoadProfile("billing")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "envKey", profile.ApiKey)
+		assert.Equal(t, "", profile.KeyCommand)
+		assert.Equal(t, "3.0", profile.Version)
+		assert.Equal(t, "https://readme.example.com", profile.Address)
+	})
+
+	t.Run("fails on unknown profiles", func(t *testing.T) {
+		writeConfigFile(t, testConfigFile, 0600)
+
+		_, err := LoadProfile("shipping")
+
+		assert.EqualError(t, err, `profile "shipping" does not exist, the config file has: billing, petstore`)
+	})
+
+	t.Run("uses the environment without a config file", func(t *testing.T) {
+		writeConfigFile(t, "", 0600)
+		t.Setenv("README_CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+		t.Setenv("README_API_KEY", "envKey")
+
+		profile, err := LoadProfile("")
+
+		assert.Nil(t, err)
+		assert.Equal(t, &Profile{ApiKey: "envKey"}, profile)
+
+		_, err = LoadProfile("petstore")
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestNewClientFromProfile(t *testing.T) {
+	t.Run("applies the profile to requests", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, _, _ := r.BasicAuth()
+			assert.Equal(t, "petKey", user)
+			assert.Equal(t, "2.0", r.Header.Get("x-readme-version"))
+			assert.Equal(t, "pets", r.Header.Get("x-team"))
+			writeJSON(w, map[string]string{"name": "Petstore"})
+		}))
+		defer server.Close()
+
+		writeConfigFile(t, "profiles:\n  default:\n    address: "+server.URL+"\n    api_key: petKey\n    version: \"2.0\"\n    headers:\n      x-team: pets\n    timeout: 30s\n", 0600)
+
+		client, err := NewClientFromProfile("")
+		assert.Nil(t, err)
+
+		project, err := client.Project.Get(context.Background())
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Petstore", project.Name)
+		assert.Equal(t, 30*time.Second, client.http.Timeout)
+	})
+
+	t.Run("runs the key command", func(t *testing.T) {
+		writeConfigFile(t, testConfigFile, 0600)
+
+		client, err := NewClientFromProfile("billing")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "billingKey", client.apiKey)
+		assert.Equal(t, "readme.example.com", client.baseUrl.Host)
+	})
+
+	t.Run("reports failing key commands", func(t *testing.T) {
+		writeConfigFile(t, "profiles:\n  broken:\n    key_command: echo denied >&2; exit 3\n", 0600)
+
+		_, err := NewClientFromProfile("broken")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "could not run key command: exit status 3: denied")
+	})
+}
@@ -21,12 +21,5 @@ type project struct {
 }
 
 func (p *project) Get(ctx context.Context) (*ProjectMetadata, error) {
-	response, err := p.client.get(ctx, "", nil)
-
-	if err != nil {
-		return nil, err
-	}
-
-	result := ProjectMetadata{}
-	return &result, p.client.decodeAndClose(response.Body, &result)
+	return getJSON[*ProjectMetadata](ctx, p.client, "", nil)
 }
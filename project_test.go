@@ -9,8 +9,7 @@ import (
 
 func TestProject(t *testing.T) {
 	t.Run("can fetch project info", func(t *testing.T) {
-		client, err := NewClient(nil)
-		assert.Nil(t, err)
+		client := newCassetteClient(t)
 
 		project, err := client.Project.Get(context.Background())
 		assert.Nil(t, err)
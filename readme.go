@@ -16,6 +16,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/brandonc/go-weblinks"
 	"github.com/google/go-querystring/query"
@@ -24,6 +25,12 @@ import (
 const (
 	userAgent = "go-readme"
 
+	// defaultRetryWait is the delay before the first retry, doubled for each retry after it
+	defaultRetryWait = 500 * time.Millisecond
+
+	// maxRetryWait is the longest a request waits to be retried, ex. for a rate limit to reset
+	maxRetryWait = time.Minute
+
 	// DefaultAddress is the default host of the readme API
 	DefaultAddress = "https://dash.readme.com"
 
@@ -58,6 +65,10 @@ type Config struct {
 
 	// HttpClient is a default pooled http client
 	HttpClient *http.Client
+
+	// MaxRetries is the number of times GET, PUT and DELETE requests are retried after a 429 or 5xx
+	// response. Defaults to 3; a negative value disables retries.
+	MaxRetries int
 }
 
 // Client is the primary object used to interact with the readme API
@@ -67,6 +78,10 @@ type Client struct {
 	headers http.Header
 	http    *http.Client
 
+	// maxRetries and retryWait control retries, see Client.retryDelay
+	maxRetries int
+	retryWait  time.Duration
+
 	// Changelogs allows interactions with Changelog API resources
 	Changelogs Changelogs
 
@@ -87,6 +102,18 @@ type Client struct {
 
 	// ApiSpecification allows interactions with ApiSpecification API resources
 	ApiSpecifications ApiSpecifications
+
+	// ApiRegistry allows interactions with the API definitions of the API registry
+	ApiRegistry ApiRegistry
+
+	// Schema allows fetching the OpenAPI definition of the readme API
+	Schema Schema
+
+	// Errors allows listing the error pages of the project
+	Errors Errors
+
+	// OutboundIPs allows listing the addresses readme sends requests from
+	OutboundIPs OutboundIPs
 }
 
 // ErrorResponse is the standard json error details given for server errors
@@ -112,6 +139,7 @@ func DefaultConfig() *Config {
 		ApiKey:     os.Getenv("README_API_KEY"),
 		Headers:    make(http.Header),
 		HttpClient: http.DefaultClient,
+		MaxRetries: 3,
 	}
 
 	config.Headers.Add("user-agent", userAgent)
@@ -120,32 +148,111 @@ func DefaultConfig() *Config {
 	return config
 }
 
+// ResponseError is returned when the API responds with an error status
+type ResponseError struct {
+	StatusCode int
+
+	// ErrorCode, Message and DocsUrl are set when the API describes the error, ex. "DOC_NOTFOUND"
+	ErrorCode string
+	Message   string
+	DocsUrl   string
+
+	// RequestID identifies the request in support tickets
+	RequestID string
+
+	// Body is the raw response body
+	Body string
+}
+
+func (e *ResponseError) Error() string {
+	if e.ErrorCode != "" {
+		return fmt.Sprintf("%s: %s (See %s)", e.ErrorCode, e.Message, e.DocsUrl)
+	}
+	return fmt.Sprintf("server error (%v): %s", e.StatusCode, e.Body)
+}
+
 func handleErrorResponse(response *http.Response) error {
 	body, err := ioutil.ReadAll(response.Body)
 	if err != nil {
 		return fmt.Errorf("could not read error body: %w", err)
 	}
 
+	result := &ResponseError{
+		StatusCode: response.StatusCode,
+		RequestID:  response.Header.Get("x-request-id"),
+		Body:       string(body),
+	}
+
 	if strings.HasPrefix(response.Header.Get("content-type"), "application/json") {
 		serverError := errorResponse{}
 		if err = json.Unmarshal(body, &serverError); err != nil {
 			return err
 		}
 
-		return fmt.Errorf("%s: %s (See %s)", serverError.ErrorCode, serverError.Message, serverError.DocsUrl)
-	} else {
-		return fmt.Errorf("server error (%v): %s", response.StatusCode, body)
+		result.ErrorCode = serverError.ErrorCode
+		result.Message = serverError.Message
+		result.DocsUrl = serverError.DocsUrl
 	}
+
+	return result
 }
 
 func (c *Client) do(ctx context.Context, method string, path string, reader io.Reader, header http.Header) (*http.Response, error) {
+	// Only requests without side effects are retried, with their body buffered to send it again
+	retries := 0
+	var body []byte
+	if idempotent(method) {
+		retries = c.maxRetries
+		if reader != nil && retries > 0 {
+			var err error
+			if body, err = ioutil.ReadAll(reader); err != nil {
+				return nil, fmt.Errorf("could not read request body: %w", err)
+			}
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+
+		response, err := c.send(ctx, method, path, reader, header)
+		if err != nil {
+			return nil, err
+		}
+
+		if response.StatusCode < 400 {
+			return response, nil
+		}
+
+		if attempt < retries && (response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500) {
+			if delay, ok := c.retryDelay(response, attempt); ok {
+				response.Body.Close()
+				log.Printf("[DEBUG] retrying %s %s in %v after status %d", method, path, delay, response.StatusCode)
+
+				timer := time.NewTimer(delay)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return nil, fmt.Errorf("could not perform %s request: %w", method, ctx.Err())
+				case <-timer.C:
+				}
+				continue
+			}
+		}
+
+		return nil, handleErrorResponse(response)
+	}
+}
+
+func (c *Client) send(ctx context.Context, method string, path string, reader io.Reader, header http.Header) (*http.Response, error) {
 	request, err := http.NewRequestWithContext(ctx, method, c.baseUrl.String()+path, reader)
 
 	if err != nil {
 		return nil, fmt.Errorf("could not create request: %w", err)
 	}
 
-	request.Header = c.headers
+	request.Header = c.headers.Clone()
 
 	for name, value := range header {
 		request.Header.Set(name, strings.Join(value, ", "))
@@ -156,19 +263,44 @@ func (c *Client) do(ctx context.Context, method string, path string, reader io.R
 		return nil, fmt.Errorf("could not perform %s request: %w", method, err)
 	}
 
-	if response.StatusCode >= 400 {
-		return nil, handleErrorResponse(response)
+	if err := captureResponse(ctx, response); err != nil {
+		return nil, fmt.Errorf("could not read response body: %w", err)
 	}
 
 	return response, nil
 }
 
-func (c *Client) post(ctx context.Context, path string, reader io.Reader) (*http.Response, error) {
-	return c.do(ctx, "POST", path, reader, nil)
+// retryDelay returns how long to wait before retrying a failed request. Rate limited requests wait
+// for the Retry-After header or until the rate limit resets, others back off exponentially. It
+// returns false when the wait would be longer than maxRetryWait.
+func (c *Client) retryDelay(response *http.Response, attempt int) (time.Duration, bool) {
+	delay := c.retryWait << attempt
+
+	if response.StatusCode == http.StatusTooManyRequests {
+		if seconds, err := strconv.Atoi(response.Header.Get("retry-after")); err == nil {
+			delay = time.Duration(seconds) * time.Second
+		} else if limit := parseRateLimit(response.Header); limit != nil && !limit.Reset.IsZero() {
+			delay = time.Until(limit.Reset)
+		}
+	}
+
+	if delay < 0 {
+		delay = 0
+	}
+	return delay, delay <= maxRetryWait
+}
+
+// idempotent reports whether a request with the method can be sent again without side effects
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
 }
 
-func (c *Client) put(ctx context.Context, path string, reader io.Reader) (*http.Response, error) {
-	return c.do(ctx, "PUT", path, reader, nil)
+func (c *Client) post(ctx context.Context, path string, reader io.Reader) (*http.Response, error) {
+	return c.do(ctx, "POST", path, reader, nil)
 }
 
 func (c *Client) get(ctx context.Context, path string, opts interface{}) (*http.Response, error) {
@@ -185,17 +317,6 @@ func (c *Client) delete(ctx context.Context, path string) (*http.Response, error
 	return c.do(ctx, "DELETE", path, nil, nil)
 }
 
-func (c *Client) getPaged(ctx context.Context, path string, opts interface{}) (*http.Response, *Pagination, error) {
-	response, err := c.get(ctx, path, opts)
-
-	if err != nil {
-		return nil, nil, err
-	}
-
-	pagination, err := c.parsePaginationResponse(&response.Header)
-	return response, pagination, err
-}
-
 func (c *Client) parsePaginationResponse(responseHeaders *http.Header) (*Pagination, error) {
 	totalCount, err := strconv.Atoi(responseHeaders.Get("x-total-count"))
 
@@ -212,13 +333,22 @@ func (c *Client) parsePaginationResponse(responseHeaders *http.Header) (*Paginat
 	}
 
 	return &Pagination{
-		Next:       links["next"].URI.String(),
-		Prev:       links["prev"].URI.String(),
-		Last:       links["last"].URI.String(),
+		Next:       linkURI(links, "next"),
+		Prev:       linkURI(links, "prev"),
+		Last:       linkURI(links, "last"),
 		TotalCount: totalCount,
 	}, nil
 }
 
+// linkURI returns the URI of the link with the specified rel, or an empty string if it is absent
+func linkURI(links weblinks.Links, rel string) string {
+	link, ok := links[rel]
+	if !ok || link == nil || link.URI == nil {
+		return ""
+	}
+	return link.URI.String()
+}
+
 func (c *Client) decodeAndClose(body io.ReadCloser, v interface{}) error {
 	defer body.Close()
 	if v != nil {
@@ -243,6 +373,18 @@ func NewClient(cfg *Config) (*Client, error) {
 		for k, v := range cfg.Headers {
 			config.Headers[k] = v
 		}
+
+		if cfg.HttpClient != nil {
+			config.HttpClient = cfg.HttpClient
+		}
+
+		if cfg.MaxRetries != 0 {
+			config.MaxRetries = cfg.MaxRetries
+		}
+	}
+
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
 	}
 
 	baseUrl, err := url.ParseRequestURI(config.Address)
@@ -258,23 +400,49 @@ func NewClient(cfg *Config) (*Client, error) {
 	config.Headers.Add("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(config.ApiKey+":")))
 
 	client := &Client{
-		baseUrl: baseUrl,
-		apiKey:  config.ApiKey,
-		headers: config.Headers,
-		http:    config.HttpClient,
+		baseUrl:    baseUrl,
+		apiKey:     config.ApiKey,
+		headers:    config.Headers,
+		http:       config.HttpClient,
+		maxRetries: config.MaxRetries,
+		retryWait:  defaultRetryWait,
 	}
-
-	client.Changelogs = &changelogs{client: client}
-	client.CustomPages = &custompages{client: client}
-	client.Docs = &docs{client: client}
-	client.Categories = &categories{client: client}
-	client.Project = &project{client: client}
-	client.Versions = &versions{client: client}
-	client.ApiSpecifications = &api_specification{client: client}
+	client.initServices()
 
 	return client, nil
 }
 
+// WithVersion returns a copy of the client that scopes every request to the specified project
+// version (semver, ex. "1.0") using the x-readme-version header
+func (c *Client) WithVersion(version string) *Client {
+	client := &Client{
+		baseUrl:    c.baseUrl,
+		apiKey:     c.apiKey,
+		headers:    c.headers.Clone(),
+		http:       c.http,
+		maxRetries: c.maxRetries,
+		retryWait:  c.retryWait,
+	}
+	client.headers.Set("x-readme-version", version)
+	client.initServices()
+
+	return client
+}
+
+func (c *Client) initServices() {
+	c.Changelogs = &changelogs{client: c}
+	c.CustomPages = &custompages{client: c}
+	c.Docs = &docs{client: c}
+	c.Categories = &categories{client: c}
+	c.Project = &project{client: c}
+	c.Versions = &versions{client: c}
+	c.ApiSpecifications = &api_specification{client: c}
+	c.ApiRegistry = &api_registry{client: c}
+	c.Schema = &schema{client: c}
+	c.Errors = &errorPages{client: c}
+	c.OutboundIPs = &outboundIPs{client: c}
+}
+
 func (c *Client) newFileUploadBody(path string) (io.Reader, http.Header, error) {
 	file, err := os.Open(path)
 	if err != nil {
@@ -1,10 +1,18 @@
 package readme
 
 import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/brandonc/go-readme/cassette"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -23,6 +31,60 @@ func newBool(v bool) *bool {
 	return &b
 }
 
+// newTestClient returns a client that sends all requests to a local server using the specified handler
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(&Config{
+		Address: server.URL,
+		ApiKey:  "testKey",
+	})
+
+	if err != nil {
+		t.Fatalf("could not create test client: %v", err)
+	}
+	client.retryWait = time.Millisecond
+
+	return client
+}
+
+// newCassetteClient returns a client that replays the requests of the test from
+// fixtures/cassettes/<test name>.yaml. Set CASSETTE_RECORD and README_API_KEY to record them against
+// the go-readme-int-test project instead.
+func newCassetteClient(t *testing.T) *Client {
+	recorder, err := cassette.New(filepath.Join("fixtures", "cassettes", t.Name()+".yaml"), cassette.ModeFromEnv())
+	if err != nil {
+		t.Fatalf("could not load cassette: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := recorder.Stop(); err != nil {
+			t.Errorf("could not save cassette: %v", err)
+		}
+	})
+
+	client, err := NewClient(&Config{HttpClient: recorder.Client()})
+	if err != nil {
+		t.Fatalf("could not create test client: %v", err)
+	}
+
+	return client
+}
+
+// writeJSON writes v as a json response body
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// writePage writes v as a single page json response body with pagination headers
+func writePage(w http.ResponseWriter, count int, v interface{}) {
+	w.Header().Set("x-total-count", strconv.Itoa(count))
+	w.Header().Set("link", `<>; rel="next", <>; rel="prev", <>; rel="last"`)
+	writeJSON(w, v)
+}
+
 func TestClient_NewClient(t *testing.T) {
 	t.Run("has expected default config", func(t *testing.T) {
 		defer setupEnvVars("testKey")()
@@ -59,3 +121,130 @@ func TestClient_NewClient(t *testing.T) {
 		assert.Nil(t, client, "expected nil client")
 	})
 }
+
+func TestClient_Retries(t *testing.T) {
+	// failing responds with the status to the first failures requests, then succeeds
+	failing := func(status int, failures int, header http.Header) (*Client, *[]string) {
+		requests := []string{}
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			requests = append(requests, r.Method+" "+string(body))
+			if len(requests) <= failures {
+				for name, values := range header {
+					w.Header()[name] = values
+				}
+				w.WriteHeader(status)
+				return
+			}
+			writeJSON(w, Doc{Slug: "getting-started"})
+		}))
+		return client, &requests
+	}
+
+	t.Run("retries idempotent requests after server errors", func(t *testing.T) {
+		client, requests := failing(http.StatusServiceUnavailable, 2, nil)
+
+		_, err := client.Docs.Update(context.Background(), "getting-started", DocUpdateOptions{Title: "Hello"})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{
+			`PUT {"title":"Hello"}`,
+			`PUT {"title":"Hello"}`,
+			`PUT {"title":"Hello"}`,
+		}, *requests)
+	})
+
+	t.Run("doesn't retry other requests", func(t *testing.T) {
+		client, requests := failing(http.StatusServiceUnavailable, 1, nil)
+
+		_, err := client.Docs.Create(context.Background(), DocCreateOptions{Title: "Hello", Category: "abc123"})
+		assert.NotNil(t, err)
+		assert.Len(t, *requests, 1)
+	})
+
+	t.Run("doesn't retry client errors", func(t *testing.T) {
+		client, requests := failing(http.StatusNotFound, 1, nil)
+
+		_, err := client.Docs.Get(context.Background(), "getting-started")
+		assert.NotNil(t, err)
+		assert.Len(t, *requests, 1)
+	})
+
+	t.Run("gives up after MaxRetries", func(t *testing.T) {
+		client, requests := failing(http.StatusBadGateway, 10, nil)
+
+		_, err := client.Docs.Get(context.Background(), "getting-started")
+		responseErr := &ResponseError{}
+		if assert.ErrorAs(t, err, &responseErr) {
+			assert.Equal(t, http.StatusBadGateway, responseErr.StatusCode)
+		}
+		assert.Len(t, *requests, 4)
+
+		client.maxRetries = 0
+		_, err = client.Docs.Get(context.Background(), "getting-started")
+		assert.NotNil(t, err)
+		assert.Len(t, *requests, 5)
+	})
+
+	t.Run("retries rate limited requests once the limit resets", func(t *testing.T) {
+		reset := strconv.FormatInt(time.Now().Add(-time.Second).Unix(), 10)
+		client, requests := failing(http.StatusTooManyRequests, 1, http.Header{"X-Ratelimit-Limit": {"100"}, "X-Ratelimit-Reset": {reset}})
+
+		_, err := client.Docs.Get(context.Background(), "getting-started")
+		assert.Nil(t, err)
+		assert.Len(t, *requests, 2)
+	})
+
+	t.Run("stops waiting when the context is done", func(t *testing.T) {
+		client, requests := failing(http.StatusTooManyRequests, 1, http.Header{"Retry-After": {"30"}})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := client.Docs.Get(ctx, "getting-started")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Len(t, *requests, 1)
+	})
+
+	t.Run("waits for the rate limit headers", func(t *testing.T) {
+		client := &Client{retryWait: time.Second}
+		response := func(status int, header http.Header) *http.Response {
+			return &http.Response{StatusCode: status, Header: header}
+		}
+		resetIn := func(d time.Duration) http.Header {
+			return http.Header{"X-Ratelimit-Limit": {"100"}, "X-Ratelimit-Reset": {strconv.FormatInt(time.Now().Add(d).Unix(), 10)}}
+		}
+
+		delay, ok := client.retryDelay(response(http.StatusServiceUnavailable, http.Header{}), 2)
+		assert.True(t, ok)
+		assert.Equal(t, 4*time.Second, delay)
+
+		delay, ok = client.retryDelay(response(http.StatusTooManyRequests, http.Header{"Retry-After": {"7"}}), 0)
+		assert.True(t, ok)
+		assert.Equal(t, 7*time.Second, delay)
+
+		delay, ok = client.retryDelay(response(http.StatusTooManyRequests, resetIn(30*time.Second)), 0)
+		assert.True(t, ok)
+		assert.InDelta(t, 30*time.Second, delay, float64(2*time.Second))
+
+		_, ok = client.retryDelay(response(http.StatusTooManyRequests, resetIn(time.Hour)), 0)
+		assert.False(t, ok)
+	})
+}
+
+func TestClient_WithVersion(t *testing.T) {
+	t.Run("scopes requests to the version", func(t *testing.T) {
+		versions := []string{}
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			versions = append(versions, r.Header.Get("x-readme-version"))
+			writeJSON(w, Doc{Slug: "getting-started"})
+		}))
+
+		_, err := client.WithVersion("2.0").Docs.Get(context.Background(), "getting-started")
+		assert.Nil(t, err)
+
+		_, err = client.Docs.Get(context.Background(), "getting-started")
+		assert.Nil(t, err)
+
+		assert.Equal(t, []string{"2.0", ""}, versions)
+	})
+}
@@ -0,0 +1,206 @@
+package readme
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/brandonc/go-readme/markdown"
+)
+
+// DocRename is the result of renaming a doc
+type DocRename struct {
+	OldSlug string
+	NewSlug string
+
+	// Doc is the renamed doc
+	Doc *Doc
+
+	// Rewritten are the pages whose links to the old slug were rewritten
+	Rewritten []*RewrittenPage
+}
+
+// RewrittenPage is a page whose links were rewritten
+type RewrittenPage struct {
+	// Kind is the kind of page, ex. LinkSourceDoc
+	Kind string
+	Slug string
+
+	// Links is the number of links that were rewritten
+	Links int
+}
+
+// DocRenameError is returned when renaming a doc fails after some changes were made. The changes
+// are rolled back; RollbackErrors lists the ones that could not be undone.
+type DocRenameError struct {
+	Err            error
+	RollbackErrors []error
+}
+
+func (e *DocRenameError) Error() string {
+	if len(e.RollbackErrors) == 0 {
+		return fmt.Sprintf("%v (changes were rolled back)", e.Err)
+	}
+
+	messages := make([]string, len(e.RollbackErrors))
+	for i, err := range e.RollbackErrors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%v (rollback failed: %s)", e.Err, strings.Join(messages, "; "))
+}
+
+func (e *DocRenameError) Unwrap() error {
+	return e.Err
+}
+
+// RenameDoc changes the slug of a doc and rewrites the links to its old slug in the docs of the
+// client's version, the custom pages and the changelogs. If an update fails, the changes already
+// made are undone and a *DocRenameError is returned.
+func (c *Client) RenameDoc(ctx context.Context, oldSlug, newSlug string) (*DocRename, error) {
+	content, err := c.fetchContent(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch docs: %w", err)
+	}
+
+	pages, err := listAllCustomPages(ctx, c.CustomPages)
+	if err != nil {
+		return nil, fmt.Errorf("could not list custom pages: %w", err)
+	}
+
+	changelogs, err := listAllChangelogs(ctx, c.Changelogs)
+	if err != nil {
+		return nil, fmt.Errorf("could not list changelogs: %w", err)
+	}
+
+	rewrite := func(url string) string {
+		return renameDocLink(url, oldSlug, newSlug)
+	}
+
+	result := &DocRename{OldSlug: oldSlug, NewSlug: newSlug, Rewritten: []*RewrittenPage{}}
+	undo := []func() error{}
+
+	fail := func(err error) (*DocRename, error) {
+		renameErr := &DocRenameError{Err: err}
+		for i := len(undo) - 1; i >= 0; i-- {
+			if err := undo[i](); err != nil {
+				renameErr.RollbackErrors = append(renameErr.RollbackErrors, err)
+			}
+		}
+		return nil, renameErr
+	}
+
+	result.Doc, err = c.Docs.Patch(ctx, oldSlug, DocPatchOptions{Slug: &newSlug})
+	if err != nil {
+		return nil, fmt.Errorf("could not rename doc %s: %w", oldSlug, err)
+	}
+
+	undo = append(undo, func() error {
+		if _, err := c.Docs.Patch(ctx, newSlug, DocPatchOptions{Slug: &oldSlug}); err != nil {
+			return fmt.Errorf("could not rename doc %s back to %s: %w", newSlug, oldSlug, err)
+		}
+		return nil
+	})
+
+	for _, slug := range sortedDocSlugs(content.docs) {
+		slug := slug
+		linked := content.docs[slug]
+		body, count := markdown.RewriteLinks(linked.Body, rewrite)
+		if count == 0 {
+			continue
+		}
+
+		if slug == oldSlug {
+			slug = newSlug
+		}
+
+		update := func(body string) error {
+			_, err := c.Docs.Patch(ctx, slug, DocPatchOptions{Body: &body})
+			return err
+		}
+
+		if err := update(body); err != nil {
+			return fail(fmt.Errorf("could not rewrite links of doc %s: %w", slug, err))
+		}
+
+		original := linked.Body
+		undo = append(undo, func() error {
+			if err := update(original); err != nil {
+				return fmt.Errorf("could not restore doc %s: %w", slug, err)
+			}
+			return nil
+		})
+		result.Rewritten = append(result.Rewritten, &RewrittenPage{Kind: LinkSourceDoc, Slug: slug, Links: count})
+	}
+
+	for _, page := range pages {
+		page := page
+		update := func(body string, html string) error {
+			_, err := c.CustomPages.Update(ctx, page.Slug, CustomPageUpdateOptions{Title: page.Title, Body: body, Html: html})
+			return err
+		}
+
+		body, html, count := page.Body, page.Html, 0
+		if page.HtmlMode {
+			html, count = markdown.RewriteLinks(page.Html, rewrite)
+		} else {
+			body, count = markdown.RewriteLinks(page.Body, rewrite)
+		}
+
+		if count == 0 {
+			continue
+		}
+
+		if err := update(body, html); err != nil {
+			return fail(fmt.Errorf("could not rewrite links of custom page %s: %w", page.Slug, err))
+		}
+
+		undo = append(undo, func() error {
+			if err := update(page.Body, page.Html); err != nil {
+				return fmt.Errorf("could not restore custom page %s: %w", page.Slug, err)
+			}
+			return nil
+		})
+		result.Rewritten = append(result.Rewritten, &RewrittenPage{Kind: LinkSourceCustomPage, Slug: page.Slug, Links: count})
+	}
+
+	for _, changelog := range changelogs {
+		changelog := changelog
+		body, count := markdown.RewriteLinks(changelog.Body, rewrite)
+		if count == 0 {
+			continue
+		}
+
+		if _, err := c.Changelogs.Update(ctx, changelog.Slug, ChangelogUpdateOptions{Body: body}); err != nil {
+			return fail(fmt.Errorf("could not rewrite links of changelog %s: %w", changelog.Slug, err))
+		}
+
+		undo = append(undo, func() error {
+			if _, err := c.Changelogs.Update(ctx, changelog.Slug, ChangelogUpdateOptions{Body: changelog.Body}); err != nil {
+				return fmt.Errorf("could not restore changelog %s: %w", changelog.Slug, err)
+			}
+			return nil
+		})
+		result.Rewritten = append(result.Rewritten, &RewrittenPage{Kind: LinkSourceChangelog, Slug: changelog.Slug, Links: count})
+	}
+
+	return result, nil
+}
+
+// renameDocLink returns the link with the doc slug replaced, if it links to the doc
+func renameDocLink(url string, oldSlug string, newSlug string) string {
+	match := linkShorthand.FindStringSubmatchIndex(url)
+	if match == nil {
+		match = linkPath.FindStringSubmatchIndex(url)
+	}
+
+	if match == nil || url[match[4]:match[5]] != oldSlug {
+		return url
+	}
+
+	switch url[match[2]:match[3]] {
+	case "doc", "docs", "ref", "reference":
+		return url[:match[4]] + newSlug + url[match[5]:]
+	}
+
+	return url
+}
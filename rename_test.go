@@ -0,0 +1,139 @@
+package readme
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// renameServer is an in-memory project that records updates
+type renameServer struct {
+	docs          map[string]*Doc
+	pages         map[string]*CustomPage
+	changelogs    map[string]*Changelog
+	failChangelog string
+}
+
+func newRenameServer() *renameServer {
+	return &renameServer{
+		docs: map[string]*Doc{
+			"setup":  {Slug: "setup", Title: "Setup", Category: "c1", Body: "Install first. See [setup](doc:setup#install)."},
+			"guide":  {Slug: "guide", Title: "Guide", Category: "c1", Body: "Start with [setup](/docs/setup) or [other](/docs/setup-advanced).\n\n```\n/docs/setup\n```"},
+			"others": {Slug: "others", Title: "Others", Category: "c1", Body: "Nothing to see"},
+		},
+		pages: map[string]*CustomPage{
+			"about": {Slug: "about", Title: "About", HtmlMode: true, Html: "<a href=\"/v1.0/docs/setup\">Setup</a>"},
+		},
+		changelogs: map[string]*Changelog{
+			"v1": {Slug: "v1", Title: "v1", Body: "Read [setup](doc:setup)."},
+		},
+	}
+}
+
+func (s *renameServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, DefaultBasePath+"/")
+	parts := strings.SplitN(path, "/", 2)
+	slug := ""
+	if len(parts) > 1 {
+		slug = parts[1]
+	}
+
+	if r.Method == http.MethodGet {
+		switch {
+		case path == "categories":
+			writePage(w, 1, []*Category{{Slug: "documentation", ID: "c1"}})
+		case path == "categories/documentation/docs":
+			writeJSON(w, []*CategoryDoc{{Slug: "setup"}, {Slug: "guide"}, {Slug: "others"}})
+		case parts[0] == "docs":
+			writeJSON(w, s.docs[slug])
+		case path == "custompages":
+			writePage(w, 1, []*CustomPage{s.pages["about"]})
+		case path == "changelogs":
+			writePage(w, 1, []*Changelog{s.changelogs["v1"]})
+		default:
+			http.NotFound(w, r)
+		}
+		return
+	}
+
+	switch parts[0] {
+	case "docs":
+		opt := DocPatchOptions{}
+		_ = json.NewDecoder(r.Body).Decode(&opt)
+		doc := *s.docs[slug]
+		if opt.Body != nil {
+			doc.Body = *opt.Body
+		}
+		if opt.Slug != nil && *opt.Slug != slug {
+			doc.Slug = *opt.Slug
+			delete(s.docs, slug)
+		}
+		s.docs[doc.Slug] = &doc
+		writeJSON(w, doc)
+	case "custompages":
+		opt := CustomPageUpdateOptions{}
+		_ = json.NewDecoder(r.Body).Decode(&opt)
+		page := *s.pages[slug]
+		page.Html = opt.Html
+		s.pages[slug] = &page
+		writeJSON(w, page)
+	case "changelogs":
+		if slug == s.failChangelog {
+			w.WriteHeader(http.StatusInternalServerError)
+			writeJSON(w, map[string]string{"error": "INTERNAL", "message": "boom"})
+			return
+		}
+		opt := ChangelogUpdateOptions{}
+		_ = json.NewDecoder(r.Body).Decode(&opt)
+		changelog := *s.changelogs[slug]
+		changelog.Body = opt.Body
+		s.changelogs[slug] = &changelog
+		writeJSON(w, changelog)
+	}
+}
+
+func TestClient_RenameDoc(t *testing.T) {
+	t.Run("renames the doc and rewrites inbound links", func(t *testing.T) {
+		server := newRenameServer()
+		client := newTestClient(t, server)
+
+		result, err := client.RenameDoc(context.Background(), "setup", "installation")
+		assert.Nil(t, err)
+
+		assert.Equal(t, "installation", result.Doc.Slug)
+		assert.Equal(t, []*RewrittenPage{
+			{Kind: LinkSourceDoc, Slug: "guide", Links: 1},
+			{Kind: LinkSourceDoc, Slug: "installation", Links: 1},
+			{Kind: LinkSourceCustomPage, Slug: "about", Links: 1},
+			{Kind: LinkSourceChangelog, Slug: "v1", Links: 1},
+		}, result.Rewritten)
+
+		assert.Equal(t, "Install first. See [setup](doc:installation#install).", server.docs["installation"].Body)
+		assert.Equal(t, "Start with [setup](/docs/installation) or [other](/docs/setup-advanced).\n\n```\n/docs/setup\n```", server.docs["guide"].Body)
+		assert.Equal(t, "<a href=\"/v1.0/docs/installation\">Setup</a>", server.pages["about"].Html)
+		assert.Equal(t, "Read [setup](doc:installation).", server.changelogs["v1"].Body)
+	})
+
+	t.Run("rolls back on failure", func(t *testing.T) {
+		server := newRenameServer()
+		server.failChangelog = "v1"
+		client := newTestClient(t, server)
+
+		result, err := client.RenameDoc(context.Background(), "setup", "installation")
+		assert.Nil(t, result)
+
+		renameErr := &DocRenameError{}
+		assert.True(t, errors.As(err, &renameErr))
+		assert.Empty(t, renameErr.RollbackErrors)
+		assert.Contains(t, err.Error(), "could not rewrite links of changelog v1")
+
+		assert.Equal(t, newRenameServer().docs, server.docs)
+		assert.Equal(t, newRenameServer().pages, server.pages)
+		assert.Equal(t, newRenameServer().changelogs, server.changelogs)
+	})
+}
@@ -0,0 +1,88 @@
+package readme
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// doJSON sends the body as JSON and decodes the JSON response. Every service request goes through
+// Client.do, so errors, headers and logging are the same for all of them.
+func doJSON[Req any, Resp any](ctx context.Context, c *Client, method string, path string, body Req) (Resp, error) {
+	var result Resp
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return result, fmt.Errorf("could not marshal request body: %w", err)
+	}
+
+	response, err := c.do(ctx, method, path, bytes.NewReader(data), nil)
+	if err != nil {
+		return result, err
+	}
+
+	return decodeJSON[Resp](c, response)
+}
+
+// getJSON sends a GET request with the url-encoded options and decodes the JSON response
+func getJSON[Resp any](ctx context.Context, c *Client, path string, opts interface{}) (Resp, error) {
+	response, err := c.get(ctx, path, opts)
+	if err != nil {
+		var result Resp
+		return result, err
+	}
+
+	return decodeJSON[Resp](c, response)
+}
+
+// decodeJSON decodes and closes the body of a response
+func decodeJSON[Resp any](c *Client, response *http.Response) (Resp, error) {
+	var result Resp
+	if err := c.decodeAndClose(response.Body, &result); err != nil {
+		return result, fmt.Errorf("could not decode response: %w", err)
+	}
+	return result, nil
+}
+
+// list fetches a page of a paginated resource with the url-encoded options
+func list[T any](ctx context.Context, c *Client, path string, opts interface{}, header http.Header) ([]*T, *Pagination, error) {
+	url, err := addOptions(path, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not encode url options: %w", err)
+	}
+
+	response, err := c.do(ctx, http.MethodGet, url, nil, header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination, err := c.parsePaginationResponse(&response.Header)
+	if err != nil {
+		response.Body.Close()
+		return nil, nil, err
+	}
+
+	items, err := decodeJSON[[]*T](c, response)
+	return items, pagination, err
+}
+
+// listAll fetches pages until the last one and returns all of their items
+func listAll[T any](fetch func(page int) ([]*T, *Pagination, error)) ([]*T, error) {
+	result := []*T{}
+
+	for page := 1; ; page++ {
+		items, pagination, err := fetch(page)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, items...)
+
+		if len(items) == 0 || pagination == nil || pagination.Next == "" {
+			return result, nil
+		}
+	}
+}
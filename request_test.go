@@ -0,0 +1,123 @@
+package readme
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testItem struct {
+	Name string `json:"name"`
+}
+
+func TestDoJSON(t *testing.T) {
+	t.Run("sends the body as json and decodes the response", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.Equal(t, "POST", r.Method)
+			assert.Equal(t, "/api/v1/items", r.URL.Path)
+			assert.Equal(t, "application/json", r.Header.Get("content-type"))
+			assert.JSONEq(t, `{"name":"first"}`, string(body))
+			writeJSON(w, map[string]string{"name": "created"})
+		}))
+
+		item, err := doJSON[testItem, *testItem](context.Background(), client, http.MethodPost, "items", testItem{Name: "first"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, &testItem{Name: "created"}, item)
+	})
+
+	t.Run("returns server errors", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("content-type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"ITEM_INVALID","message":"invalid item"}`))
+		}))
+
+		item, err := doJSON[testItem, *testItem](context.Background(), client, http.MethodPut, "items/first", testItem{})
+
+		var responseErr *ResponseError
+		assert.True(t, errors.As(err, &responseErr))
+		assert.Equal(t, http.StatusBadRequest, responseErr.StatusCode)
+		assert.Nil(t, item)
+	})
+
+	t.Run("wraps decode errors", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("not json"))
+		}))
+
+		_, err := getJSON[*testItem](context.Background(), client, "items/first", nil)
+
+		assert.Contains(t, err.Error(), "could not decode response: ")
+	})
+}
+
+func TestList(t *testing.T) {
+	t.Run("decodes a page with its pagination", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "perPage=2", r.URL.RawQuery)
+			assert.Equal(t, "1.0", r.Header.Get("x-readme-version"))
+			writePage(w, 3, []map[string]string{{"name": "first"}, {"name": "second"}})
+		}))
+
+		header := http.Header{}
+		header.Set("x-readme-version", "1.0")
+		items, pagination, err := list[testItem](context.Background(), client, "items", CategoriesListOptions{PerPage: 2}, header)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []*testItem{{Name: "first"}, {Name: "second"}}, items)
+		assert.Equal(t, 3, pagination.TotalCount)
+	})
+
+	t.Run("requires pagination headers", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, []string{})
+		}))
+
+		_, _, err := list[testItem](context.Background(), client, "items", nil, nil)
+
+		assert.Contains(t, err.Error(), "could not get total count from paginated response")
+	})
+}
+
+func TestListAll(t *testing.T) {
+	pages := map[int][]*testItem{
+		1: {{Name: "first"}, {Name: "second"}},
+		2: {{Name: "third"}},
+	}
+
+	t.Run("fetches pages until there is no next page", func(t *testing.T) {
+		items, err := listAll(func(page int) ([]*testItem, *Pagination, error) {
+			pagination := &Pagination{}
+			if page == 1 {
+				pagination.Next = "/items?page=2"
+			}
+			return pages[page], pagination, nil
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, []*testItem{{Name: "first"}, {Name: "second"}, {Name: "third"}}, items)
+	})
+
+	t.Run("stops at an empty page", func(t *testing.T) {
+		items, err := listAll(func(page int) ([]*testItem, *Pagination, error) {
+			return pages[page], &Pagination{Next: "/items?page=next"}, nil
+		})
+
+		assert.Nil(t, err)
+		assert.Len(t, items, 3)
+	})
+
+	t.Run("returns errors", func(t *testing.T) {
+		_, err := listAll(func(page int) ([]*testItem, *Pagination, error) {
+			return nil, nil, errors.New("server error")
+		})
+
+		assert.EqualError(t, err, "server error")
+	})
+}
@@ -0,0 +1,85 @@
+package readme
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Response is the metadata of an API response, captured with WithResponse
+type Response struct {
+	StatusCode int
+	Header     http.Header
+
+	// RequestID identifies the request in support tickets, from the x-request-id header
+	RequestID string
+
+	// RateLimit is set when the API sends rate limit headers
+	RateLimit *RateLimit
+
+	// Body is the raw response body
+	Body []byte
+}
+
+// RateLimit is the rate limit state of the API key after a request
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+type responseKey struct{}
+
+// WithResponse returns a context that captures the response metadata of the requests made with it
+// into response. When a method makes several requests, ex. to fetch every page, response holds
+// the last one.
+//
+//	response := readme.Response{}
+//	doc, err := client.Docs.Get(readme.WithResponse(ctx, &response), "getting-started")
+//	log.Printf("request %s: %d", response.RequestID, response.StatusCode)
+func WithResponse(ctx context.Context, response *Response) context.Context {
+	return context.WithValue(ctx, responseKey{}, response)
+}
+
+// captureResponse fills the Response of the context, if any. The body is read and replaced so
+// that it can still be decoded.
+func captureResponse(ctx context.Context, response *http.Response) error {
+	target, ok := ctx.Value(responseKey{}).(*Response)
+	if !ok || target == nil {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	*target = Response{
+		StatusCode: response.StatusCode,
+		Header:     response.Header.Clone(),
+		RequestID:  response.Header.Get("x-request-id"),
+		RateLimit:  parseRateLimit(response.Header),
+		Body:       body,
+	}
+
+	return err
+}
+
+// parseRateLimit reads the x-ratelimit-limit, x-ratelimit-remaining and x-ratelimit-reset (unix
+// seconds) headers
+func parseRateLimit(header http.Header) *RateLimit {
+	limit, err := strconv.Atoi(header.Get("x-ratelimit-limit"))
+	if err != nil {
+		return nil
+	}
+
+	result := &RateLimit{Limit: limit}
+	result.Remaining, _ = strconv.Atoi(header.Get("x-ratelimit-remaining"))
+	if reset, err := strconv.ParseInt(header.Get("x-ratelimit-reset"), 10, 64); err == nil {
+		result.Reset = time.Unix(reset, 0)
+	}
+
+	return result
+}
@@ -0,0 +1,77 @@
+package readme
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithResponse(t *testing.T) {
+	t.Run("captures the metadata of a response", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("x-request-id", "c3a1b9e2")
+			w.Header().Set("x-ratelimit-limit", "100")
+			w.Header().Set("x-ratelimit-remaining", "97")
+			w.Header().Set("x-ratelimit-reset", "1700000000")
+			writeJSON(w, map[string]string{"slug": "intro"})
+		}))
+
+		response := Response{}
+		doc, err := client.Docs.Get(WithResponse(context.Background(), &response), "intro")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "intro", doc.Slug)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, "c3a1b9e2", response.RequestID)
+		assert.Equal(t, "application/json", response.Header.Get("content-type"))
+		assert.Equal(t, &RateLimit{Limit: 100, Remaining: 97, Reset: time.Unix(1700000000, 0)}, response.RateLimit)
+		assert.JSONEq(t, `{"slug":"intro"}`, string(response.Body))
+	})
+
+	t.Run("captures error responses", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("x-request-id", "d4b2c0f3")
+			w.Header().Set("content-type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"DOC_NOTFOUND","message":"The doc couldn't be found."}`))
+		}))
+
+		response := Response{}
+		_, err := client.Docs.Get(WithResponse(context.Background(), &response), "missing")
+
+		responseErr := &ResponseError{}
+		assert.True(t, errors.As(err, &responseErr))
+		assert.Equal(t, "DOC_NOTFOUND", responseErr.ErrorCode)
+		assert.Equal(t, "d4b2c0f3", responseErr.RequestID)
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+		assert.Equal(t, "d4b2c0f3", response.RequestID)
+		assert.Nil(t, response.RateLimit)
+		assert.Contains(t, string(response.Body), "DOC_NOTFOUND")
+	})
+
+	t.Run("holds the last response of methods with several requests", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			page := r.URL.Query().Get("page")
+			w.Header().Set("x-request-id", "page-"+page)
+			if page == "1" {
+				w.Header().Set("x-total-count", "1")
+				w.Header().Set("link", `</categories?page=2>; rel="next"`)
+				writeJSON(w, []map[string]string{{"slug": "guides"}})
+			} else {
+				w.Header().Set("x-total-count", "1")
+				writeJSON(w, []string{})
+			}
+		}))
+
+		response := Response{}
+		categories, err := listAllCategories(WithResponse(context.Background(), &response), client.Categories)
+
+		assert.Nil(t, err)
+		assert.Len(t, categories, 1)
+		assert.Equal(t, "page-2", response.RequestID)
+	})
+}
@@ -0,0 +1,322 @@
+package readme
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	// ScheduleKindDoc schedules a doc
+	ScheduleKindDoc = "doc"
+
+	// ScheduleKindChangelog schedules a changelog
+	ScheduleKindChangelog = "changelog"
+
+	// ScheduleKindCustomPage schedules a custom page
+	ScheduleKindCustomPage = "custompage"
+)
+
+const (
+	// ScheduleActionPublish makes an item visible by setting Hidden to false
+	ScheduleActionPublish = "publish"
+
+	// ScheduleActionUnpublish hides an item by setting Hidden to true
+	ScheduleActionUnpublish = "unpublish"
+)
+
+// Schedule is the list of items to publish or unpublish at specific times
+type Schedule struct {
+	Items []*ScheduleItem `json:"items"`
+}
+
+// ScheduleItem schedules the publishing and/or unpublishing of a single doc, changelog or custom page
+type ScheduleItem struct {
+	// Kind is the kind of item, ex. ScheduleKindDoc
+	Kind string `json:"kind"`
+
+	// Slug of the item
+	Slug string `json:"slug"`
+
+	// Version of the project the doc belongs to (semver, ex. "1.0"). Only used by docs.
+	Version string `json:"version,omitempty"`
+
+	// PublishAt is the time the item becomes visible
+	PublishAt *time.Time `json:"publishAt,omitempty"`
+
+	// UnpublishAt is the time the item becomes hidden
+	UnpublishAt *time.Time `json:"unpublishAt,omitempty"`
+}
+
+// ScheduledAction is a single publish or unpublish action of a schedule item
+type ScheduledAction struct {
+	Item   *ScheduleItem
+	Action string
+	At     time.Time
+
+	// superseded are the earlier due actions of the item, recorded as applied along with this one
+	superseded []*ScheduledAction
+}
+
+// key uniquely identifies the action, including its time, so that rescheduling an item applies it again
+func (a *ScheduledAction) key() string {
+	return fmt.Sprintf("%s:%s:%s:%s@%s", a.Action, a.Item.Kind, a.Item.Version, a.Item.Slug, a.At.UTC().Format(time.RFC3339))
+}
+
+// LoadSchedule reads a json schedule file
+func LoadSchedule(path string) (*Schedule, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read schedule: %w", err)
+	}
+
+	schedule := Schedule{}
+	if err := json.Unmarshal(data, &schedule); err != nil {
+		return nil, fmt.Errorf("could not parse schedule: %w", err)
+	}
+
+	for i, item := range schedule.Items {
+		switch item.Kind {
+		case ScheduleKindDoc, ScheduleKindChangelog, ScheduleKindCustomPage:
+		default:
+			return nil, fmt.Errorf("schedule item %d has unknown kind %q", i, item.Kind)
+		}
+
+		if item.Slug == "" {
+			return nil, fmt.Errorf("schedule item %d has no slug", i)
+		}
+	}
+
+	return &schedule, nil
+}
+
+// schedulerState is the record of applied actions persisted between runs
+type schedulerState struct {
+	Applied map[string]time.Time `json:"applied"`
+}
+
+// Scheduler flips the Hidden state of docs, changelogs and custom pages according to a Schedule.
+// Applied actions are persisted to StatePath so that each action is applied exactly once, even
+// across restarts.
+type Scheduler struct {
+	Client   *Client
+	Schedule *Schedule
+
+	// StatePath is the json file used to persist applied actions
+	StatePath string
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// Due returns the actions whose time has passed and that have not been applied yet, oldest first.
+// When several actions of an item are due, only the latest is returned, so that catching up never
+// publishes an item whose embargo has already ended.
+func (s *Scheduler) Due() ([]*ScheduledAction, error) {
+	state, err := s.loadState()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.due(state), nil
+}
+
+func (s *Scheduler) due(state *schedulerState) []*ScheduledAction {
+	now := s.now()
+	result := []*ScheduledAction{}
+
+	for _, item := range s.Schedule.Items {
+		actions := []*ScheduledAction{}
+		if item.PublishAt != nil {
+			actions = append(actions, &ScheduledAction{Item: item, Action: ScheduleActionPublish, At: *item.PublishAt})
+		}
+		if item.UnpublishAt != nil {
+			actions = append(actions, &ScheduledAction{Item: item, Action: ScheduleActionUnpublish, At: *item.UnpublishAt})
+		}
+
+		// The latest due action wins, unpublishing when both are due at the same time
+		var latest *ScheduledAction
+		pending := []*ScheduledAction{}
+		for _, action := range actions {
+			if action.At.After(now) {
+				continue
+			}
+			if latest == nil || !action.At.Before(latest.At) {
+				latest = action
+			}
+			if _, applied := state.Applied[action.key()]; !applied {
+				pending = append(pending, action)
+			}
+		}
+
+		if latest == nil {
+			continue
+		}
+		if _, applied := state.Applied[latest.key()]; applied {
+			continue
+		}
+
+		for _, action := range pending {
+			if action != latest {
+				latest.superseded = append(latest.superseded, action)
+			}
+		}
+		result = append(result, latest)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].At.Before(result[j].At)
+	})
+
+	return result
+}
+
+// CatchUp applies every due action that has not been applied yet and returns the applied actions.
+// The state is persisted after each action, so a failure part way leaves completed actions recorded.
+func (s *Scheduler) CatchUp(ctx context.Context) ([]*ScheduledAction, error) {
+	state, err := s.loadState()
+	if err != nil {
+		return nil, err
+	}
+
+	applied := []*ScheduledAction{}
+	for _, action := range s.due(state) {
+		if err := s.apply(ctx, action); err != nil {
+			return applied, fmt.Errorf("could not %s %s %s: %w", action.Action, action.Item.Kind, action.Item.Slug, err)
+		}
+
+		state.Applied[action.key()] = s.now()
+		for _, superseded := range action.superseded {
+			state.Applied[superseded.key()] = s.now()
+		}
+		if err := s.saveState(state); err != nil {
+			return applied, err
+		}
+
+		log.Printf("[INFO] %sed %s %s scheduled at %s", action.Action, action.Item.Kind, action.Item.Slug, action.At.Format(time.RFC3339))
+		applied = append(applied, action)
+	}
+
+	return applied, nil
+}
+
+// Run catches up every interval until the context is cancelled
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.CatchUp(ctx); err != nil {
+			log.Printf("[ERROR] %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) apply(ctx context.Context, action *ScheduledAction) error {
+	hidden := action.Action == ScheduleActionUnpublish
+
+	switch action.Item.Kind {
+	case ScheduleKindDoc:
+		client := s.Client
+		if action.Item.Version != "" {
+			client = client.WithVersion(action.Item.Version)
+		}
+
+		_, err := client.Docs.Patch(ctx, action.Item.Slug, DocPatchOptions{
+			Hidden: &hidden,
+		})
+		return err
+
+	case ScheduleKindChangelog:
+		_, err := s.Client.Changelogs.Update(ctx, action.Item.Slug, ChangelogUpdateOptions{
+			Hidden: &hidden,
+		})
+		return err
+
+	case ScheduleKindCustomPage:
+		page, err := s.Client.CustomPages.Get(ctx, action.Item.Slug)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.Client.CustomPages.Update(ctx, action.Item.Slug, CustomPageUpdateOptions{
+			Html:   page.Html,
+			Hidden: &hidden,
+		})
+		return err
+
+	default:
+		return fmt.Errorf("unknown kind %q", action.Item.Kind)
+	}
+}
+
+func (s *Scheduler) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+func (s *Scheduler) loadState() (*schedulerState, error) {
+	state := &schedulerState{Applied: make(map[string]time.Time)}
+
+	data, err := ioutil.ReadFile(s.StatePath)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read scheduler state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("could not parse scheduler state: %w", err)
+	}
+
+	if state.Applied == nil {
+		state.Applied = make(map[string]time.Time)
+	}
+
+	return state, nil
+}
+
+// saveState writes the state to a temporary file and renames it over StatePath, so a crash never
+// leaves a partially written state behind
+func (s *Scheduler) saveState(state *schedulerState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal scheduler state: %w", err)
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(s.StatePath), ".scheduler-state-*")
+	if err != nil {
+		return fmt.Errorf("could not write scheduler state: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("could not write scheduler state: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("could not write scheduler state: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), s.StatePath); err != nil {
+		return fmt.Errorf("could not write scheduler state: %w", err)
+	}
+
+	return nil
+}
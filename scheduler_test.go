@@ -0,0 +1,109 @@
+package readme
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadSchedule(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("reads items", func(t *testing.T) {
+		path := filepath.Join(dir, "schedule.json")
+		ioutil.WriteFile(path, []byte(`{"items": [{"kind": "doc", "slug": "launch", "version": "2.0", "publishAt": "2021-10-01T09:00:00Z"}]}`), 0644)
+
+		schedule, err := LoadSchedule(path)
+		assert.Nil(t, err)
+		assert.Len(t, schedule.Items, 1)
+		assert.Equal(t, "2.0", schedule.Items[0].Version)
+		assert.Equal(t, time.Date(2021, 10, 1, 9, 0, 0, 0, time.UTC), schedule.Items[0].PublishAt.UTC())
+	})
+
+	t.Run("rejects unknown kinds", func(t *testing.T) {
+		path := filepath.Join(dir, "invalid.json")
+		ioutil.WriteFile(path, []byte(`{"items": [{"kind": "category", "slug": "launch"}]}`), 0644)
+
+		_, err := LoadSchedule(path)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestScheduler_CatchUp(t *testing.T) {
+	at := func(hour int) *time.Time {
+		t := time.Date(2021, 10, 1, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	updates := []string{}
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			writeJSON(w, Doc{Title: "Launch", Category: "abc123"})
+			return
+		}
+
+		body := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		updates = append(updates, r.Header.Get("x-readme-version")+" "+r.URL.Path+" hidden="+jsonString(body["hidden"]))
+		writeJSON(w, body)
+	}))
+
+	now := *at(12)
+	scheduler := &Scheduler{
+		Client: client,
+		Schedule: &Schedule{Items: []*ScheduleItem{
+			{Kind: ScheduleKindDoc, Slug: "launch", Version: "2.0", PublishAt: at(9)},
+			{Kind: ScheduleKindChangelog, Slug: "promo", PublishAt: at(8), UnpublishAt: at(11)},
+			{Kind: ScheduleKindCustomPage, Slug: "later", PublishAt: at(13)},
+			{Kind: ScheduleKindChangelog, Slug: "sale", PublishAt: at(10), UnpublishAt: at(14)},
+		}},
+		StatePath: filepath.Join(t.TempDir(), "state.json"),
+		Now:       func() time.Time { return now },
+	}
+
+	t.Run("applies due actions in order", func(t *testing.T) {
+		applied, err := scheduler.CatchUp(context.Background())
+		assert.Nil(t, err)
+		assert.Len(t, applied, 3)
+		assert.Equal(t, []string{
+			"2.0 /api/v1/docs/launch hidden=false",
+			" /api/v1/changelogs/sale hidden=false",
+			" /api/v1/changelogs/promo hidden=true",
+		}, updates, "promo is only unpublished, its embargo ended before the catch up")
+	})
+
+	t.Run("does not apply actions twice across restarts", func(t *testing.T) {
+		restarted := *scheduler
+		applied, err := restarted.CatchUp(context.Background())
+		assert.Nil(t, err)
+		assert.Empty(t, applied)
+		assert.Len(t, updates, 3)
+	})
+
+	t.Run("applies actions once they become due", func(t *testing.T) {
+		now = *at(14)
+
+		due, err := scheduler.Due()
+		assert.Nil(t, err)
+		assert.Len(t, due, 2)
+
+		applied, err := scheduler.CatchUp(context.Background())
+		assert.Nil(t, err)
+		assert.Len(t, applied, 2)
+		assert.Equal(t, []string{
+			" /api/v1/custompages/later hidden=false",
+			" /api/v1/changelogs/sale hidden=true",
+		}, updates[3:])
+	})
+}
+
+func jsonString(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,20 @@
+package readme
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Schema allows fetching the OpenAPI definition of the readme API
+type Schema interface {
+	Get(ctx context.Context) (json.RawMessage, error)
+}
+
+type schema struct {
+	client *Client
+}
+
+// Get the OpenAPI definition of the readme API
+func (s *schema) Get(ctx context.Context) (json.RawMessage, error) {
+	return getJSON[json.RawMessage](ctx, s.client, "schema", nil)
+}
@@ -0,0 +1,21 @@
+package readme
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema_Get(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/schema", r.URL.Path)
+		writeJSON(w, map[string]interface{}{"openapi": "3.0.2", "info": map[string]string{"title": "API Endpoints"}})
+	}))
+
+	schema, err := client.Schema.Get(context.Background())
+
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"openapi":"3.0.2","info":{"title":"API Endpoints"}}`, string(schema))
+}
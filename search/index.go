@@ -0,0 +1,488 @@
+// Package search is an embeddable full-text index of ReadMe docs, custom pages and changelogs. It
+// ranks matches with BM25, stems English words and weighs matches in titles and excerpts above
+// matches in bodies, so content can be searched without the API.
+package search
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	readme "github.com/brandonc/go-readme"
+)
+
+const (
+	// KindDoc is a doc
+	KindDoc = "doc"
+
+	// KindCustomPage is a custom page
+	KindCustomPage = "custompage"
+
+	// KindChangelog is a changelog
+	KindChangelog = "changelog"
+)
+
+// indexVersion is the version of the serialized index format
+const indexVersion = 1
+
+// The fields of a document, in the order of posting frequencies
+const (
+	fieldTitle = iota
+	fieldExcerpt
+	fieldBody
+	fieldCount
+)
+
+// Boosts weigh the matches in each field of a document
+type Boosts struct {
+	Title   float64
+	Excerpt float64
+	Body    float64
+}
+
+// DefaultBoosts are the boosts of new indexes
+var DefaultBoosts = Boosts{Title: 3, Excerpt: 2, Body: 1}
+
+// Document is a page of the index
+type Document struct {
+	// ID identifies the document in the index, ex. "doc:getting-started"
+	ID   string
+	Kind string
+	Slug string
+
+	Title   string
+	Excerpt string
+
+	// Body is the plain text of the page, see PlainText
+	Body string
+
+	// URL is the path of the page on the hub, ex. "/docs/getting-started"
+	URL    string
+	Hidden bool
+}
+
+// Result is a document that matched a query
+type Result struct {
+	Document *Document
+	Score    float64
+
+	// Snippet is the part of the body that best matches the query
+	Snippet string
+
+	// Highlights are the byte ranges of the snippet that matched a query term
+	Highlights [][2]int
+}
+
+// QueryOptions are the options available when querying an index
+type QueryOptions struct {
+	// Limit is the maximum number of results. Defaults to 10.
+	Limit int
+
+	// Kinds restricts results to the given kinds of documents, ex. KindDoc
+	Kinds []string
+
+	// IncludeHidden includes hidden pages in the results
+	IncludeHidden bool
+}
+
+// posting records how often a term appears in each field of a document
+type posting struct {
+	Doc  int
+	Freq [fieldCount]int
+}
+
+// Index is an inverted index of documents
+type Index struct {
+	// Boosts weigh matches in each field, see DefaultBoosts
+	Boosts Boosts
+
+	// K1 and B are the BM25 term frequency saturation and length normalization parameters
+	K1 float64
+	B  float64
+
+	documents []*Document
+	ids       map[string]int
+	postings  map[string][]*posting
+	lengths   [][fieldCount]int
+	totals    [fieldCount]int
+}
+
+// indexFile is the serialized form of an index
+type indexFile struct {
+	Version   int
+	Boosts    Boosts
+	K1        float64
+	B         float64
+	Documents []*Document
+	Postings  map[string][]*posting
+	Lengths   [][fieldCount]int
+}
+
+// NewIndex returns an empty index using the default boosts and BM25 parameters
+func NewIndex() *Index {
+	return &Index{
+		Boosts:   DefaultBoosts,
+		K1:       1.2,
+		B:        0.75,
+		ids:      make(map[string]int),
+		postings: make(map[string][]*posting),
+	}
+}
+
+// Len returns the number of documents in the index
+func (ix *Index) Len() int {
+	return len(ix.documents)
+}
+
+// Add indexes a document, replacing any document with the same ID
+func (ix *Index) Add(doc *Document) {
+	i, exists := ix.ids[doc.ID]
+	if exists {
+		ix.remove(i)
+		ix.documents[i] = doc
+	} else {
+		i = len(ix.documents)
+		ix.ids[doc.ID] = i
+		ix.documents = append(ix.documents, doc)
+		ix.lengths = append(ix.lengths, [fieldCount]int{})
+	}
+
+	frequencies := map[string]*posting{}
+	for field, text := range [fieldCount]string{doc.Title, doc.Excerpt, doc.Body} {
+		tokens := tokenize(text)
+		ix.lengths[i][field] = len(tokens)
+		ix.totals[field] += len(tokens)
+
+		for _, token := range tokens {
+			p, ok := frequencies[token.term]
+			if !ok {
+				p = &posting{Doc: i}
+				frequencies[token.term] = p
+				ix.postings[token.term] = append(ix.postings[token.term], p)
+			}
+			p.Freq[field]++
+		}
+	}
+}
+
+// remove deletes the postings of the document at the index
+func (ix *Index) remove(i int) {
+	old := ix.documents[i]
+	for field, text := range [fieldCount]string{old.Title, old.Excerpt, old.Body} {
+		ix.totals[field] -= ix.lengths[i][field]
+		for _, token := range tokenize(text) {
+			postings := ix.postings[token.term]
+			for j, p := range postings {
+				if p.Doc == i {
+					postings = append(postings[:j], postings[j+1:]...)
+					break
+				}
+			}
+			if len(postings) == 0 {
+				delete(ix.postings, token.term)
+			} else {
+				ix.postings[token.term] = postings
+			}
+		}
+	}
+}
+
+// AddDoc indexes a doc. Docs marked as API reference pages link to /reference/.
+func (ix *Index) AddDoc(doc *readme.Doc) {
+	path := "/docs/"
+	if doc.IsReference || doc.IsAPI {
+		path = "/reference/"
+	}
+
+	ix.Add(&Document{
+		ID:      KindDoc + ":" + doc.Slug,
+		Kind:    KindDoc,
+		Slug:    doc.Slug,
+		Title:   doc.Title,
+		Excerpt: doc.Excerpt,
+		Body:    PlainText(doc.Body),
+		URL:     path + doc.Slug,
+		Hidden:  doc.Hidden,
+	})
+}
+
+// AddCustomPage indexes a custom page
+func (ix *Index) AddCustomPage(page *readme.CustomPage) {
+	body := page.Body
+	if page.HtmlMode {
+		body = page.Html
+	}
+
+	ix.Add(&Document{
+		ID:     KindCustomPage + ":" + page.Slug,
+		Kind:   KindCustomPage,
+		Slug:   page.Slug,
+		Title:  page.Title,
+		Body:   PlainText(body),
+		URL:    "/page/" + page.Slug,
+		Hidden: page.Hidden,
+	})
+}
+
+// AddChangelog indexes a changelog
+func (ix *Index) AddChangelog(changelog *readme.Changelog) {
+	ix.Add(&Document{
+		ID:     KindChangelog + ":" + changelog.Slug,
+		Kind:   KindChangelog,
+		Slug:   changelog.Slug,
+		Title:  changelog.Title,
+		Body:   PlainText(changelog.Body),
+		URL:    "/changelog/" + changelog.Slug,
+		Hidden: changelog.Hidden,
+	})
+}
+
+// AddExport indexes every page of a project export
+func (ix *Index) AddExport(export *readme.ProjectExport) {
+	references := readme.ReferenceCategoryIDs(export.Categories)
+
+	for _, doc := range export.Docs {
+		if !doc.IsReference && readme.IsReferenceDoc(doc, references) {
+			reference := *doc
+			reference.IsReference = true
+			doc = &reference
+		}
+		ix.AddDoc(doc)
+	}
+
+	for _, page := range export.CustomPages {
+		ix.AddCustomPage(page)
+	}
+
+	for _, changelog := range export.Changelogs {
+		ix.AddChangelog(changelog)
+	}
+}
+
+// Search returns the documents matching any term of the query, best match first
+func (ix *Index) Search(query string, opt QueryOptions) []*Result {
+	if opt.Limit <= 0 {
+		opt.Limit = 10
+	}
+
+	kinds := map[string]bool{}
+	for _, kind := range opt.Kinds {
+		kinds[kind] = true
+	}
+
+	terms := map[string]bool{}
+	for _, token := range tokenize(query) {
+		terms[token.term] = true
+	}
+
+	boosts := [fieldCount]float64{ix.Boosts.Title, ix.Boosts.Excerpt, ix.Boosts.Body}
+	averages := [fieldCount]float64{}
+	for field := range averages {
+		if len(ix.documents) > 0 {
+			averages[field] = float64(ix.totals[field]) / float64(len(ix.documents))
+		}
+	}
+
+	scores := map[int]float64{}
+	for term := range terms {
+		postings := ix.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+
+		n, df := float64(len(ix.documents)), float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+		for _, p := range postings {
+			for field, freq := range p.Freq {
+				if freq == 0 || averages[field] == 0 {
+					continue
+				}
+				tf := float64(freq)
+				norm := 1 - ix.B + ix.B*float64(ix.lengths[p.Doc][field])/averages[field]
+				scores[p.Doc] += boosts[field] * idf * tf * (ix.K1 + 1) / (tf + ix.K1*norm)
+			}
+		}
+	}
+
+	results := []*Result{}
+	for i, score := range scores {
+		doc := ix.documents[i]
+		if (len(kinds) > 0 && !kinds[doc.Kind]) || (doc.Hidden && !opt.IncludeHidden) {
+			continue
+		}
+		results = append(results, &Result{Document: doc, Score: score})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Document.ID < results[j].Document.ID
+	})
+
+	if len(results) > opt.Limit {
+		results = results[:opt.Limit]
+	}
+
+	for _, result := range results {
+		result.Snippet, result.Highlights = snippet(result.Document.Body, terms)
+	}
+
+	return results
+}
+
+// snippetWords is the number of words in a snippet
+const snippetWords = 30
+
+// snippet returns the window of the text with the most matching terms, and the ranges of the matches
+func snippet(text string, terms map[string]bool) (string, [][2]int) {
+	tokens := tokenize(text)
+	if len(tokens) == 0 {
+		return "", [][2]int{}
+	}
+
+	best, bestHits, hits := 0, -1, 0
+	for i := range tokens {
+		if terms[tokens[i].term] {
+			hits++
+		}
+		if i >= snippetWords && terms[tokens[i-snippetWords].term] {
+			hits--
+		}
+		if start := i - snippetWords + 1; hits > bestHits {
+			if start < 0 {
+				start = 0
+			}
+			best, bestHits = start, hits
+		}
+	}
+
+	last := best + snippetWords - 1
+	if last >= len(tokens) {
+		last = len(tokens) - 1
+	}
+
+	from, to := tokens[best].start, tokens[last].end
+	if best == 0 {
+		from = 0
+	}
+	if last == len(tokens)-1 {
+		to = len(text)
+	}
+
+	prefix, suffix := "", ""
+	if from > 0 {
+		prefix = "…"
+	}
+	if to < len(text) {
+		suffix = "…"
+	}
+
+	// Trim before computing the highlights so that their offsets match the snippet
+	trimmed := strings.TrimLeftFunc(text[from:to], unicode.IsSpace)
+	from = to - len(trimmed)
+	to = from + len(strings.TrimRightFunc(trimmed, unicode.IsSpace))
+
+	highlights := [][2]int{}
+	for _, token := range tokens[best : last+1] {
+		if terms[token.term] {
+			start := token.start - from + len(prefix)
+			highlights = append(highlights, [2]int{start, start + token.end - token.start})
+		}
+	}
+
+	return prefix + text[from:to] + suffix, highlights
+}
+
+// WriteTo serializes the index
+func (ix *Index) WriteTo(w io.Writer) (int64, error) {
+	counter := &countingWriter{w: w}
+	err := gob.NewEncoder(counter).Encode(&indexFile{
+		Version:   indexVersion,
+		Boosts:    ix.Boosts,
+		K1:        ix.K1,
+		B:         ix.B,
+		Documents: ix.documents,
+		Postings:  ix.postings,
+		Lengths:   ix.lengths,
+	})
+
+	if err != nil {
+		return counter.n, fmt.Errorf("could not encode index: %w", err)
+	}
+
+	return counter.n, nil
+}
+
+// ReadIndex reads an index serialized with WriteTo
+func ReadIndex(r io.Reader) (*Index, error) {
+	file := &indexFile{}
+	if err := gob.NewDecoder(r).Decode(file); err != nil {
+		return nil, fmt.Errorf("could not decode index: %w", err)
+	}
+
+	if file.Version != indexVersion {
+		return nil, fmt.Errorf("unsupported index version %d", file.Version)
+	}
+
+	ix := NewIndex()
+	ix.Boosts, ix.K1, ix.B = file.Boosts, file.K1, file.B
+	ix.documents = file.Documents
+	ix.lengths = file.Lengths
+
+	if file.Postings != nil {
+		ix.postings = file.Postings
+	}
+
+	for i, doc := range ix.documents {
+		ix.ids[doc.ID] = i
+		for field := range ix.totals {
+			ix.totals[field] += ix.lengths[i][field]
+		}
+	}
+
+	return ix, nil
+}
+
+// Save writes the index to a file
+func (ix *Index) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not create index file: %w", err)
+	}
+
+	if _, err := ix.WriteTo(file); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// LoadIndex reads an index saved with Save
+func LoadIndex(path string) (*Index, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open index file: %w", err)
+	}
+	defer file.Close()
+
+	return ReadIndex(file)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
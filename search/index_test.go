@@ -0,0 +1,226 @@
+package search
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	readme "github.com/brandonc/go-readme"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestIndex() *Index {
+	index := NewIndex()
+	index.AddDoc(&readme.Doc{
+		Slug:    "authentication",
+		Title:   "Authentication",
+		Excerpt: "Sign requests with an API key",
+		Body:    "Every request must include your **API key** in the `Authorization` header. Keys are created in the [dashboard](doc:dashboard).",
+	})
+	index.AddDoc(&readme.Doc{
+		Slug:    "rate-limits",
+		Title:   "Rate limits",
+		Excerpt: "How many requests you can make",
+		Body:    "Requests are limited to 100 per minute for each key. Limited requests respond with a 429 status.",
+	})
+	index.AddDoc(&readme.Doc{
+		Slug:        "list-pets",
+		Title:       "List pets",
+		Body:        "Returns every pet in the store.",
+		IsReference: true,
+	})
+	index.AddDoc(&readme.Doc{
+		Slug:   "internal-keys",
+		Title:  "Rotating keys",
+		Body:   "Rotate the signing keys every month.",
+		Hidden: true,
+	})
+	index.AddCustomPage(&readme.CustomPage{
+		Slug:  "support",
+		Title: "Support",
+		Body:  "Email us if a key stops working.",
+	})
+	index.AddChangelog(&readme.Changelog{
+		Slug:  "new-keys",
+		Title: "Scoped API keys",
+		Body:  "You can now create keys that are limited to a single project.",
+	})
+	return index
+}
+
+func resultIDs(results []*Result) []string {
+	ids := []string{}
+	for _, result := range results {
+		ids = append(ids, result.Document.ID)
+	}
+	return ids
+}
+
+func TestIndex_Search(t *testing.T) {
+	index := newTestIndex()
+
+	t.Run("ranks title matches above body matches", func(t *testing.T) {
+		results := index.Search("keys", QueryOptions{})
+
+		assert.Equal(t, []string{"changelog:new-keys", "doc:authentication", "custompage:support", "doc:rate-limits"}, resultIDs(results))
+		for i := 1; i < len(results); i++ {
+			assert.GreaterOrEqual(t, results[i-1].Score, results[i].Score)
+		}
+	})
+
+	t.Run("stems the query", func(t *testing.T) {
+		results := index.Search("limiting", QueryOptions{})
+
+		assert.Equal(t, "doc:rate-limits", results[0].Document.ID)
+		assert.Contains(t, resultIDs(results), "changelog:new-keys")
+	})
+
+	t.Run("sets URLs by kind", func(t *testing.T) {
+		results := index.Search("pets", QueryOptions{})
+
+		assert.Len(t, results, 1)
+		assert.Equal(t, "/reference/list-pets", results[0].Document.URL)
+		assert.Equal(t, "/page/support", index.Search("email", QueryOptions{})[0].Document.URL)
+	})
+
+	t.Run("filters by kind", func(t *testing.T) {
+		results := index.Search("keys", QueryOptions{Kinds: []string{KindChangelog, KindCustomPage}})
+
+		assert.Equal(t, []string{"changelog:new-keys", "custompage:support"}, resultIDs(results))
+	})
+
+	t.Run("leaves out hidden pages", func(t *testing.T) {
+		assert.Empty(t, index.Search("rotate", QueryOptions{}))
+		assert.Equal(t, []string{"doc:internal-keys"}, resultIDs(index.Search("rotate", QueryOptions{IncludeHidden: true})))
+	})
+
+	t.Run("limits results", func(t *testing.T) {
+		assert.Len(t, index.Search("keys", QueryOptions{Limit: 2}), 2)
+	})
+
+	t.Run("ignores stop words and unknown terms", func(t *testing.T) {
+		assert.Empty(t, index.Search("the", QueryOptions{}))
+		assert.Empty(t, index.Search("webhooks", QueryOptions{}))
+	})
+
+	t.Run("applies boosts", func(t *testing.T) {
+		index := newTestIndex()
+		index.Boosts = Boosts{Title: 0, Excerpt: 0, Body: 1}
+
+		results := index.Search("keys", QueryOptions{Kinds: []string{KindDoc}})
+
+		assert.Equal(t, []string{"doc:authentication", "doc:rate-limits"}, resultIDs(results))
+	})
+}
+
+func TestIndex_Add(t *testing.T) {
+	t.Run("replaces documents with the same ID", func(t *testing.T) {
+		index := newTestIndex()
+		index.AddDoc(&readme.Doc{Slug: "rate-limits", Title: "Quotas", Body: "Each project has a monthly quota."})
+
+		assert.Equal(t, 6, index.Len())
+		assert.Empty(t, index.Search("minute", QueryOptions{}))
+		assert.Equal(t, []string{"doc:rate-limits"}, resultIDs(index.Search("quota", QueryOptions{})))
+	})
+
+	t.Run("indexes exports", func(t *testing.T) {
+		index := NewIndex()
+		index.AddExport(&readme.ProjectExport{
+			Categories: []*readme.Category{{ID: "api", Reference: true}},
+			Docs:       []*readme.Doc{{Slug: "get-pet", Title: "Get a pet", Category: "api"}},
+			CustomPages: []*readme.CustomPage{
+				{Slug: "legal", Title: "Legal", HtmlMode: true, Html: "<p>Pet <b>terms</b></p>"},
+			},
+		})
+
+		results := index.Search("pet", QueryOptions{})
+
+		assert.Equal(t, []string{"doc:get-pet", "custompage:legal"}, resultIDs(results))
+		assert.Equal(t, "/reference/get-pet", results[0].Document.URL)
+		assert.Equal(t, "Pet terms", results[1].Document.Body)
+	})
+}
+
+func TestIndex_Snippets(t *testing.T) {
+	t.Run("highlights matching words", func(t *testing.T) {
+		results := newTestIndex().Search("authorization header", QueryOptions{})
+
+		result := results[0]
+		assert.Equal(t, "doc:authentication", result.Document.ID)
+		assert.Equal(t, "Every request must include your API key in the Authorization header. Keys are created in the dashboard.", result.Snippet)
+		assert.Equal(t, [][2]int{{47, 60}, {61, 67}}, result.Highlights)
+		for _, h := range result.Highlights {
+			assert.Contains(t, []string{"Authorization", "header"}, result.Snippet[h[0]:h[1]])
+		}
+	})
+
+	t.Run("highlights after trimming whitespace", func(t *testing.T) {
+		index := NewIndex()
+		index.Add(&Document{ID: "doc:keys", Kind: KindDoc, Slug: "keys", Title: "Keys", Body: "\n  Rotate your key often.  \n"})
+
+		result := index.Search("rotate", QueryOptions{})[0]
+
+		assert.Equal(t, "Rotate your key often.", result.Snippet)
+		assert.Equal(t, [][2]int{{0, 6}}, result.Highlights)
+	})
+
+	t.Run("picks the window with the most matches", func(t *testing.T) {
+		index := NewIndex()
+		words := strings.Repeat("lorem ipsum dolor ", 20)
+		index.AddDoc(&readme.Doc{Slug: "long", Title: "Long", Body: words + "webhooks retry failed webhooks. " + words})
+
+		result := index.Search("webhook", QueryOptions{})[0]
+
+		assert.True(t, strings.HasPrefix(result.Snippet, "…"))
+		assert.True(t, strings.HasSuffix(result.Snippet, "…"))
+		assert.Len(t, result.Highlights, 2)
+		for _, h := range result.Highlights {
+			assert.Equal(t, "webhooks", result.Snippet[h[0]:h[1]])
+		}
+	})
+}
+
+func TestIndex_Serialization(t *testing.T) {
+	index := newTestIndex()
+	index.Boosts.Title = 5
+
+	t.Run("round trips through a writer", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		n, err := index.WriteTo(buffer)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(buffer.Len()), n)
+
+		loaded, err := ReadIndex(buffer)
+		assert.Nil(t, err)
+		assert.Equal(t, index.Len(), loaded.Len())
+		assert.Equal(t, 5.0, loaded.Boosts.Title)
+		assert.Equal(t, index.Search("keys", QueryOptions{}), loaded.Search("keys", QueryOptions{}))
+
+		loaded.AddDoc(&readme.Doc{Slug: "authentication", Title: "Auth"})
+		assert.Equal(t, index.Len(), loaded.Len())
+	})
+
+	t.Run("saves and loads files", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "index.gob")
+		assert.Nil(t, index.Save(path))
+
+		loaded, err := LoadIndex(path)
+		assert.Nil(t, err)
+		assert.Equal(t, resultIDs(index.Search("limits", QueryOptions{})), resultIDs(loaded.Search("limits", QueryOptions{})))
+	})
+
+	t.Run("fails on invalid data", func(t *testing.T) {
+		_, err := ReadIndex(strings.NewReader("not an index"))
+		assert.Error(t, err)
+
+		_, err = LoadIndex(filepath.Join(t.TempDir(), "missing.gob"))
+		assert.Error(t, err)
+	})
+}
+
+func TestPlainText(t *testing.T) {
+	body := "# Setup\n\nRead the [guide](doc:guide) and **install** it.\n\n```bash\nnpm install\n```\n"
+
+	assert.Equal(t, "Setup\nRead the guide and install it.\nnpm install", PlainText(body))
+}
@@ -0,0 +1,38 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStem(t *testing.T) {
+	words := map[string]string{
+		"connect":        "connect",
+		"connected":      "connect",
+		"connecting":     "connect",
+		"connections":    "connect",
+		"caresses":       "caress",
+		"ponies":         "poni",
+		"cats":           "cat",
+		"feed":           "feed",
+		"agreed":         "agre",
+		"hopping":        "hop",
+		"hoping":         "hope",
+		"falling":        "fall",
+		"relational":     "relat",
+		"generalization": "gener",
+		"happy":          "happi",
+		"electricity":    "electr",
+		"adjustment":     "adjust",
+		"controlling":    "control",
+		"is":             "is",
+		"api2":           "api2",
+	}
+
+	for word, stem := range words {
+		t.Run(word, func(t *testing.T) {
+			assert.Equal(t, stem, Stem(word))
+		})
+	}
+}
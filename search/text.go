@@ -0,0 +1,112 @@
+package search
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/brandonc/go-readme/markdown"
+)
+
+// stopWords are common English words that are not indexed
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "in": true, "is": true, "it": true, "of": true, "on": true, "or": true,
+	"that": true, "the": true, "this": true, "to": true, "was": true, "with": true,
+}
+
+var (
+	htmlTags    = regexp.MustCompile(`<[^>]*>`)
+	inlineLinks = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	inlineMarks = regexp.MustCompile("[*_`~]+")
+)
+
+// token is a word of a text and its position
+type token struct {
+	term  string
+	start int
+	end   int
+}
+
+// tokenize splits text into lowercase, stemmed terms, leaving out stop words
+func tokenize(text string) []token {
+	result := []token{}
+
+	start := -1
+	flush := func(end int) {
+		if start == -1 {
+			return
+		}
+		word := strings.ToLower(text[start:end])
+		if !stopWords[word] {
+			result = append(result, token{term: Stem(word), start: start, end: end})
+		}
+		start = -1
+	}
+
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			if start == -1 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+	}
+	flush(len(text))
+
+	return result
+}
+
+// PlainText returns the readable text of a ReadMe-flavored Markdown body, without markup, magic block
+// JSON or HTML tags
+func PlainText(body string) string {
+	parts := []string{}
+	add := func(text string) {
+		text = inlineLinks.ReplaceAllString(text, "$1")
+		text = htmlTags.ReplaceAllString(text, " ")
+		text = inlineMarks.ReplaceAllString(text, "")
+		text = html.UnescapeString(text)
+		if text = strings.Join(strings.Fields(text), " "); text != "" {
+			parts = append(parts, text)
+		}
+	}
+
+	markdown.Inspect(markdown.Parse(body), func(node markdown.Node) bool {
+		switch n := node.(type) {
+		case *markdown.Heading:
+			add(n.Text)
+		case *markdown.Paragraph:
+			add(n.Text)
+		case *markdown.Blockquote:
+			add(PlainText(n.Text))
+		case *markdown.List:
+			for _, item := range n.Items {
+				add(PlainText(item.Content))
+			}
+		case *markdown.CodeBlock:
+			add(n.Code)
+		case *markdown.Callout:
+			add(n.Title)
+			add(PlainText(n.Body))
+		case *markdown.Table:
+			add(strings.Join(n.Header, " "))
+			for _, row := range n.Rows {
+				add(strings.Join(row, " "))
+			}
+		case *markdown.Image:
+			add(n.Alt)
+			add(n.Caption)
+		case *markdown.Embed:
+			add(n.Title)
+		case *markdown.Recipe:
+			add(n.Title)
+		case *markdown.HTML:
+			add(n.HTML)
+		}
+		return true
+	})
+
+	return strings.Join(parts, "\n")
+}
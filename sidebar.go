@@ -0,0 +1,317 @@
+package readme
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// DocPosition places a doc next to a sibling. When both are empty, the doc is placed last.
+type DocPosition struct {
+	// After is the slug of the sibling the doc is placed after
+	After string
+
+	// Before is the slug of the sibling the doc is placed before
+	Before string
+}
+
+// DocOrderChange is a doc whose order was changed by a sidebar operation
+type DocOrderChange struct {
+	Slug string
+
+	// OldOrder is the order of the doc before the change. It is -1 for docs moved from another group.
+	OldOrder int
+	NewOrder int
+}
+
+// sidebarGroup is a list of sibling docs: the top level docs of a category or the children of a doc
+type sidebarGroup struct {
+	categoryID string
+	parentID   string
+	docs       []*CategoryDoc
+}
+
+// MoveDoc moves a doc into the category with the given slug, next to a sibling. The sibling may be
+// a child doc, in which case the moved doc is nested under the same parent. Only the docs whose
+// order needs to change are updated.
+func (c *Client) MoveDoc(ctx context.Context, slug string, category string, position DocPosition) ([]*DocOrderChange, error) {
+	if position.After != "" && position.Before != "" {
+		return nil, fmt.Errorf("could not move doc %s: only one of after and before can be specified", slug)
+	}
+
+	target, err := c.Categories.Get(ctx, category)
+	if err != nil {
+		return nil, fmt.Errorf("could not get category %s: %w", category, err)
+	}
+
+	docs, err := c.Categories.ListDocs(ctx, category)
+	if err != nil {
+		return nil, fmt.Errorf("could not list docs of category %s: %w", category, err)
+	}
+
+	group := &sidebarGroup{categoryID: target.ID, docs: docs}
+	sibling := position.After
+	if sibling == "" {
+		sibling = position.Before
+	}
+
+	if sibling != "" {
+		if group = findSidebarGroup(target.ID, "", docs, sibling); group == nil {
+			return nil, fmt.Errorf("could not find doc %s in category %s", sibling, category)
+		}
+	}
+
+	return c.placeDoc(ctx, slug, group, position)
+}
+
+// NestDoc moves a doc under a parent doc, after the parent's existing children
+func (c *Client) NestDoc(ctx context.Context, child string, parent string) ([]*DocOrderChange, error) {
+	parentDoc, err := c.Docs.Get(ctx, parent)
+	if err != nil {
+		return nil, fmt.Errorf("could not get doc %s: %w", parent, err)
+	}
+
+	categories, err := listAllCategories(ctx, c.Categories)
+	if err != nil {
+		return nil, fmt.Errorf("could not list categories: %w", err)
+	}
+
+	for _, category := range categories {
+		if category.ID != parentDoc.Category {
+			continue
+		}
+
+		docs, err := c.Categories.ListDocs(ctx, category.Slug)
+		if err != nil {
+			return nil, fmt.Errorf("could not list docs of category %s: %w", category.Slug, err)
+		}
+
+		group := findSidebarGroup(category.ID, "", docs, parent)
+		if group == nil {
+			break
+		}
+
+		for _, doc := range group.docs {
+			if doc.Slug == parent {
+				return c.placeDoc(ctx, child, &sidebarGroup{categoryID: category.ID, parentID: doc.ID, docs: doc.Children}, DocPosition{})
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("could not find doc %s in its category", parent)
+}
+
+// ReorderDocs orders a group of sibling docs of the category with the given slug: either all of the
+// top level docs, or all of the children of a doc. Only the docs whose order needs to change are
+// updated.
+func (c *Client) ReorderDocs(ctx context.Context, category string, slugs []string) ([]*DocOrderChange, error) {
+	if len(slugs) == 0 {
+		return []*DocOrderChange{}, nil
+	}
+
+	docs, err := c.Categories.ListDocs(ctx, category)
+	if err != nil {
+		return nil, fmt.Errorf("could not list docs of category %s: %w", category, err)
+	}
+
+	group := findSidebarGroup("", "", docs, slugs[0])
+	if group == nil {
+		return nil, fmt.Errorf("could not find doc %s in category %s", slugs[0], category)
+	}
+
+	bySlug := make(map[string]*CategoryDoc, len(group.docs))
+	for _, doc := range group.docs {
+		bySlug[doc.Slug] = doc
+	}
+
+	ordered := make([]*CategoryDoc, 0, len(slugs))
+	for _, slug := range slugs {
+		doc, ok := bySlug[slug]
+		if !ok {
+			return nil, fmt.Errorf("could not reorder docs: %s is not a sibling of %s", slug, slugs[0])
+		}
+		delete(bySlug, slug)
+		ordered = append(ordered, doc)
+	}
+
+	if len(bySlug) > 0 {
+		return nil, fmt.Errorf("could not reorder docs: %d siblings of %s are missing", len(bySlug), slugs[0])
+	}
+
+	return c.applyOrder(ctx, ordered, "", nil)
+}
+
+// placeDoc inserts the doc into the group at the position and updates the orders of the group
+func (c *Client) placeDoc(ctx context.Context, slug string, group *sidebarGroup, position DocPosition) ([]*DocOrderChange, error) {
+	var moved *CategoryDoc
+	siblings := make([]*CategoryDoc, 0, len(group.docs)+1)
+	for _, doc := range group.docs {
+		if doc.Slug == slug {
+			moved = doc
+			continue
+		}
+		siblings = append(siblings, doc)
+	}
+
+	if moved == nil {
+		// the doc comes from another group, so its current order means nothing here
+		moved = &CategoryDoc{Slug: slug, Order: -1}
+	}
+
+	index := len(siblings)
+	for i, doc := range siblings {
+		if doc.Slug == position.After {
+			index = i + 1
+		} else if doc.Slug == position.Before {
+			index = i
+		}
+	}
+
+	ordered := append(siblings[:index:index], moved)
+	ordered = append(ordered, siblings[index:]...)
+
+	return c.applyOrder(ctx, ordered, slug, group)
+}
+
+// applyOrder updates the docs whose order must change for the docs to appear in the given order. The
+// moved doc, if any, is always updated and placed in the group.
+func (c *Client) applyOrder(ctx context.Context, ordered []*CategoryDoc, moved string, group *sidebarGroup) ([]*DocOrderChange, error) {
+	orders := make([]int, len(ordered))
+	fixed := make([]bool, len(ordered))
+	for i, doc := range ordered {
+		orders[i] = doc.Order
+		fixed[i] = doc.Slug != moved && doc.Order >= 0
+	}
+
+	result := []*DocOrderChange{}
+	for i, order := range minimalOrders(orders, fixed) {
+		doc := ordered[i]
+		if order == doc.Order && doc.Slug != moved {
+			continue
+		}
+
+		if err := c.updateDocOrder(ctx, doc.Slug, order, doc.Slug == moved, group); err != nil {
+			return result, err
+		}
+
+		result = append(result, &DocOrderChange{Slug: doc.Slug, OldOrder: doc.Order, NewOrder: order})
+	}
+
+	return result, nil
+}
+
+func (c *Client) updateDocOrder(ctx context.Context, slug string, order int, move bool, group *sidebarGroup) error {
+	opt := DocPatchOptions{Order: &order}
+	if move {
+		opt.Category = &group.categoryID
+		opt.ParentDoc = &group.parentID
+	}
+
+	if _, err := c.Docs.Patch(ctx, slug, opt); err != nil {
+		return fmt.Errorf("could not update order of doc %s: %w", slug, err)
+	}
+
+	return nil
+}
+
+// findSidebarGroup finds the group of siblings that contains the doc with the slug
+func findSidebarGroup(categoryID string, parentID string, docs []*CategoryDoc, slug string) *sidebarGroup {
+	for _, doc := range docs {
+		if doc.Slug == slug {
+			return &sidebarGroup{categoryID: categoryID, parentID: parentID, docs: docs}
+		}
+	}
+
+	for _, doc := range docs {
+		if group := findSidebarGroup(categoryID, doc.ID, doc.Children, slug); group != nil {
+			return group
+		}
+	}
+
+	return nil
+}
+
+// minimalOrders returns new orders for a sequence of docs so that the orders strictly increase,
+// changing as few orders as possible. The longest increasing run of fixed orders is kept and the
+// other docs are given orders in the gaps between them. When a gap is too small, the sequence is
+// renumbered from zero.
+func minimalOrders(orders []int, fixed []bool) []int {
+	result := make([]int, len(orders))
+	keep := longestIncreasing(orders, fixed)
+
+	last := -1
+	for i := 0; i < len(orders); {
+		if keep[i] {
+			result[i] = orders[i]
+			last = orders[i]
+			i++
+			continue
+		}
+
+		// find the next kept order, which bounds the run of docs to place
+		end := i
+		for end < len(orders) && !keep[end] {
+			end++
+		}
+
+		if end < len(orders) && orders[end]-last-1 < end-i {
+			return renumberOrders(orders)
+		}
+
+		for j := i; j < end; j++ {
+			last++
+			result[j] = last
+		}
+		i = end
+	}
+
+	return result
+}
+
+// renumberOrders numbers the sequence from zero
+func renumberOrders(orders []int) []int {
+	result := make([]int, len(orders))
+	for i := range orders {
+		result[i] = i
+	}
+	return result
+}
+
+// longestIncreasing marks the longest strictly increasing subsequence of the fixed orders
+func longestIncreasing(orders []int, fixed []bool) []bool {
+	// tails[k] is the index of the smallest tail of an increasing subsequence of length k+1
+	tails := []int{}
+	previous := make([]int, len(orders))
+
+	for i, order := range orders {
+		if !fixed[i] {
+			continue
+		}
+
+		k := sort.Search(len(tails), func(k int) bool {
+			return orders[tails[k]] >= order
+		})
+
+		previous[i] = -1
+		if k > 0 {
+			previous[i] = tails[k-1]
+		}
+
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+
+	result := make([]bool, len(orders))
+	if len(tails) == 0 {
+		return result
+	}
+
+	for i := tails[len(tails)-1]; i != -1; i = previous[i] {
+		result[i] = true
+	}
+
+	return result
+}
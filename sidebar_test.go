@@ -0,0 +1,134 @@
+package readme
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinimalOrders(t *testing.T) {
+	t.Run("keeps the longest increasing run", func(t *testing.T) {
+		result := minimalOrders([]int{0, 30, 10, 20}, []bool{true, true, true, true})
+		assert.Equal(t, []int{0, 1, 10, 20}, result)
+	})
+
+	t.Run("places unknown orders in gaps", func(t *testing.T) {
+		result := minimalOrders([]int{0, -1, 5}, []bool{true, false, true})
+		assert.Equal(t, []int{0, 1, 5}, result)
+	})
+
+	t.Run("renumbers when gaps are too small", func(t *testing.T) {
+		result := minimalOrders([]int{0, 1, -1, 2}, []bool{true, true, false, true})
+		assert.Equal(t, []int{0, 1, 2, 3}, result)
+	})
+
+	t.Run("handles duplicate orders", func(t *testing.T) {
+		result := minimalOrders([]int{999, 999, 999}, []bool{true, true, true})
+		assert.Equal(t, []int{0, 1, 999}, result)
+	})
+}
+
+func TestClient_Sidebar(t *testing.T) {
+	newServer := func(updates map[string]DocPatchOptions) http.Handler {
+		tree := map[string][]*CategoryDoc{
+			"guides": {
+				{Slug: "intro", ID: "d1", Order: 0},
+				{Slug: "setup", ID: "d2", Order: 10, Children: []*CategoryDoc{{Slug: "linux", ID: "d5", Order: 0}}},
+				{Slug: "usage", ID: "d3", Order: 20},
+				{Slug: "faq", ID: "d4", Order: 30},
+			},
+			"reference": {{Slug: "errors", ID: "d6", Order: 0}},
+		}
+		categories := []*Category{{Slug: "guides", ID: "c1"}, {Slug: "reference", ID: "c2"}}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path := strings.TrimPrefix(r.URL.Path, DefaultBasePath+"/")
+
+			switch {
+			case r.Method == http.MethodPut && strings.HasPrefix(path, "docs/"):
+				opt := DocPatchOptions{}
+				_ = json.NewDecoder(r.Body).Decode(&opt)
+				updates[strings.TrimPrefix(path, "docs/")] = opt
+				writeJSON(w, Doc{})
+			case strings.HasPrefix(path, "docs/"):
+				slug := strings.TrimPrefix(path, "docs/")
+				category := "c1"
+				if slug == "errors" {
+					category = "c2"
+				}
+				writeJSON(w, Doc{Slug: slug, Title: strings.ToUpper(slug), Category: category})
+			case path == "categories":
+				writePage(w, len(categories), categories)
+			case strings.HasSuffix(path, "/docs"):
+				writeJSON(w, tree[strings.TrimSuffix(strings.TrimPrefix(path, "categories/"), "/docs")])
+			case strings.HasPrefix(path, "categories/"):
+				for _, category := range categories {
+					if category.Slug == strings.TrimPrefix(path, "categories/") {
+						writeJSON(w, category)
+					}
+				}
+			default:
+				http.NotFound(w, r)
+			}
+		})
+	}
+
+	t.Run("reorders with minimal changes", func(t *testing.T) {
+		updates := map[string]DocPatchOptions{}
+		client := newTestClient(t, newServer(updates))
+
+		changes, err := client.ReorderDocs(context.Background(), "guides", []string{"intro", "usage", "setup", "faq"})
+		assert.Nil(t, err)
+
+		assert.Equal(t, []*DocOrderChange{{Slug: "usage", OldOrder: 20, NewOrder: 1}}, changes)
+		assert.Len(t, updates, 1)
+		assert.Equal(t, 1, *updates["usage"].Order)
+		assert.Nil(t, updates["usage"].Category)
+	})
+
+	t.Run("rejects incomplete groups", func(t *testing.T) {
+		client := newTestClient(t, newServer(map[string]DocPatchOptions{}))
+
+		_, err := client.ReorderDocs(context.Background(), "guides", []string{"intro", "usage"})
+		assert.EqualError(t, err, "could not reorder docs: 2 siblings of intro are missing")
+	})
+
+	t.Run("moves a doc across categories", func(t *testing.T) {
+		updates := map[string]DocPatchOptions{}
+		client := newTestClient(t, newServer(updates))
+
+		changes, err := client.MoveDoc(context.Background(), "errors", "guides", DocPosition{Before: "usage"})
+		assert.Nil(t, err)
+
+		assert.Equal(t, []*DocOrderChange{{Slug: "errors", OldOrder: -1, NewOrder: 11}}, changes)
+		assert.Equal(t, "c1", *updates["errors"].Category)
+		assert.Equal(t, "", *updates["errors"].ParentDoc)
+	})
+
+	t.Run("moves a doc next to a child doc", func(t *testing.T) {
+		updates := map[string]DocPatchOptions{}
+		client := newTestClient(t, newServer(updates))
+
+		changes, err := client.MoveDoc(context.Background(), "faq", "guides", DocPosition{Before: "linux"})
+		assert.Nil(t, err)
+
+		assert.Equal(t, []*DocOrderChange{{Slug: "faq", OldOrder: -1, NewOrder: 0}, {Slug: "linux", OldOrder: 0, NewOrder: 1}}, changes)
+		assert.Equal(t, "d2", *updates["faq"].ParentDoc)
+	})
+
+	t.Run("nests a doc", func(t *testing.T) {
+		updates := map[string]DocPatchOptions{}
+		client := newTestClient(t, newServer(updates))
+
+		changes, err := client.NestDoc(context.Background(), "usage", "setup")
+		assert.Nil(t, err)
+
+		assert.Equal(t, []*DocOrderChange{{Slug: "usage", OldOrder: -1, NewOrder: 1}}, changes)
+		assert.Equal(t, "d2", *updates["usage"].ParentDoc)
+		assert.Equal(t, "c1", *updates["usage"].Category)
+	})
+}
@@ -1,10 +1,8 @@
 package readme
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
+	"net/http"
 )
 
 type versions struct {
@@ -84,62 +82,26 @@ func (c *versions) Delete(ctx context.Context, versionId string) error {
 
 // Update an existing custompage by versionId (semver, ex "1.0")
 func (c *versions) Update(ctx context.Context, versionId string, version VersionUpdateOptions) (*Version, error) {
-	bodyBytes, err := json.Marshal(version)
-
-	if err != nil {
-		return nil, fmt.Errorf("could not marshal request body: %w", err)
-	}
-
-	response, err := c.client.put(ctx, "version/"+versionId, bytes.NewBuffer(bodyBytes))
-
-	if err != nil {
-		return nil, err
-	}
-
-	result := Version{}
-	return &result, c.client.decodeAndClose(response.Body, &result)
+	return doJSON[VersionUpdateOptions, *Version](ctx, c.client, http.MethodPut, "version/"+versionId, version)
 }
 
 // Create a new changelog
 func (c *versions) Create(ctx context.Context, version VersionCreateOptions) (*Version, error) {
-	bodyBytes, err := json.Marshal(version)
-
-	if err != nil {
-		return nil, fmt.Errorf("could not marshal request body: %w", err)
-	}
-
-	response, err := c.client.post(ctx, "version", bytes.NewBuffer(bodyBytes))
-
-	if err != nil {
-		return nil, err
-	}
-
-	result := Version{}
-	return &result, c.client.decodeAndClose(response.Body, &result)
+	return doJSON[VersionCreateOptions, *Version](ctx, c.client, http.MethodPost, "version", version)
 }
 
 // Get the Version specified by the versionId (semver, ex. "1.0")
 func (c *versions) Get(ctx context.Context, versionId string) (*Version, error) {
-	response, err := c.client.get(ctx, "version/"+versionId, nil)
-
-	if err != nil {
-		return nil, err
-	}
-
-	result := Version{}
-
-	return &result, c.client.decodeAndClose(response.Body, &result)
+	return getJSON[*Version](ctx, c.client, "version/"+versionId, nil)
 }
 
 // List the Versions
 func (c *versions) List(ctx context.Context) (*VersionsList, error) {
-	response, err := c.client.get(ctx, "version", nil)
+	items, err := getJSON[[]*VersionListItem](ctx, c.client, "version", nil)
 
 	if err != nil {
 		return nil, err
 	}
 
-	result := VersionsList{}
-
-	return &result, c.client.decodeAndClose(response.Body, &result.Items)
+	return &VersionsList{Items: items}, nil
 }
@@ -9,9 +9,7 @@ import (
 
 func TestVersions_List(t *testing.T) {
 	t.Run("can list versions", func(t *testing.T) {
-		client, err := NewClient(nil)
-
-		assert.Nil(t, err)
+		client := newCassetteClient(t)
 
 		list, err := client.Versions.List(context.Background())
 
@@ -22,9 +20,7 @@ func TestVersions_List(t *testing.T) {
 
 func TestVersions_CreateUpdateDelete(t *testing.T) {
 	t.Run("can create versions", func(t *testing.T) {
-		client, err := NewClient(nil)
-
-		assert.Nil(t, err)
+		client := newCassetteClient(t)
 
 		opt := VersionCreateOptions{
 			Version:      "2.0",
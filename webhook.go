@@ -0,0 +1,181 @@
+package readme
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookSignatureHeader is the header ReadMe signs personalized docs webhook requests with
+const WebhookSignatureHeader = "readme-signature"
+
+// DefaultWebhookTolerance is how old a webhook signature may be by default
+const DefaultWebhookTolerance = 30 * time.Minute
+
+// maxWebhookBody limits the size of webhook request bodies
+const maxWebhookBody = 1 << 20
+
+var (
+	// ErrWebhookSignature is returned when a webhook request is not signed with the secret
+	ErrWebhookSignature = errors.New("invalid webhook signature")
+
+	// ErrWebhookExpired is returned when the signature of a webhook request is outside the tolerance
+	ErrWebhookExpired = errors.New("webhook signature expired")
+
+	// ErrUserNotFound can be returned by a WebhookHandler's GetUser function when there is no user
+	// with the email. The handler responds with 404 Not Found.
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// WebhookHandler is an http.Handler for the personalized docs webhook. ReadMe posts the email of a
+// user signed with the webhook secret of the project, and the handler responds with the data of the
+// user, see UserData.
+type WebhookHandler struct {
+	// Secret is the webhook secret of the project
+	Secret string
+
+	// Tolerance is how far the signature time may be from the current time. Defaults to
+	// DefaultWebhookTolerance.
+	Tolerance time.Duration
+
+	// GetUser returns the data of the user with the email, or ErrUserNotFound
+	GetUser func(ctx context.Context, email string) (UserData, error)
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// webhookRequest is the body of a webhook request
+type webhookRequest struct {
+	Email string `json:"email"`
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("allow", http.MethodPost)
+		writeWebhookError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		writeWebhookError(w, http.StatusBadRequest, "could not read body")
+		return
+	}
+
+	now := time.Now
+	if h.Now != nil {
+		now = h.Now
+	}
+
+	tolerance := h.Tolerance
+	if tolerance == 0 {
+		tolerance = DefaultWebhookTolerance
+	}
+
+	if err := VerifyWebhookSignature(body, r.Header.Get(WebhookSignatureHeader), h.Secret, tolerance, now()); err != nil {
+		writeWebhookError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	request := webhookRequest{}
+	if err := json.Unmarshal(body, &request); err != nil || request.Email == "" {
+		writeWebhookError(w, http.StatusBadRequest, "body must be a json object with an email")
+		return
+	}
+
+	user, err := h.GetUser(r.Context(), request.Email)
+	if errors.Is(err, ErrUserNotFound) {
+		writeWebhookError(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		writeWebhookError(w, http.StatusInternalServerError, "could not get user")
+		return
+	}
+
+	if user.Email == "" {
+		user.Email = request.Email
+	}
+
+	type fields UserData
+	data, err := mergeVariables(user.Variables, (*fields)(&user))
+	if err != nil {
+		writeWebhookError(w, http.StatusInternalServerError, "could not marshal user")
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.Write(data)
+}
+
+func writeWebhookError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// WebhookSignature returns the readme-signature header value of a webhook body signed at the time
+func WebhookSignature(body []byte, secret string, t time.Time) string {
+	timestamp := strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+	return "t=" + timestamp + ",v0=" + webhookHMAC(body, secret, timestamp)
+}
+
+// VerifyWebhookSignature checks a readme-signature header value of the form "t=<unix
+// milliseconds>,v0=<hex HMAC-SHA256 of the timestamp, a dot and the body>", and that the time of the
+// signature is within the tolerance of now
+func VerifyWebhookSignature(body []byte, signature string, secret string, tolerance time.Duration, now time.Time) error {
+	if secret == "" {
+		return errors.New("a webhook secret is required to verify signatures")
+	}
+
+	timestamp, signatures := "", []string{}
+	for _, part := range strings.Split(signature, ",") {
+		name, value := part, ""
+		if i := strings.Index(part, "="); i != -1 {
+			name, value = part[:i], part[i+1:]
+		}
+
+		switch strings.TrimSpace(name) {
+		case "t":
+			timestamp = value
+		case "v0":
+			signatures = append(signatures, value)
+		}
+	}
+
+	milliseconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return fmt.Errorf("%w: malformed %s header", ErrWebhookSignature, WebhookSignatureHeader)
+	}
+
+	signedAt := time.Unix(0, milliseconds*int64(time.Millisecond))
+	if age := now.Sub(signedAt); age > tolerance || age < -tolerance {
+		return ErrWebhookExpired
+	}
+
+	expected := webhookHMAC(body, secret, timestamp)
+	for _, s := range signatures {
+		if hmac.Equal([]byte(s), []byte(expected)) {
+			return nil
+		}
+	}
+
+	return ErrWebhookSignature
+}
+
+func webhookHMAC(body []byte, secret string, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,142 @@
+package readme
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testWebhookSecret = "rdme_webhook_secret"
+
+var testWebhookTime = time.Date(2021, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func TestWebhookSignature(t *testing.T) {
+	body := []byte(`{"email":"ada@example.com"}`)
+
+	t.Run("signs the timestamp and body", func(t *testing.T) {
+		assert.Equal(t,
+			"t=1630497600000,v0="+webhookHMAC(body, testWebhookSecret, "1630497600000"),
+			WebhookSignature(body, testWebhookSecret, testWebhookTime))
+	})
+
+	t.Run("verifies signatures", func(t *testing.T) {
+		signature := WebhookSignature(body, testWebhookSecret, testWebhookTime)
+
+		assert.Nil(t, VerifyWebhookSignature(body, signature, testWebhookSecret, time.Minute, testWebhookTime.Add(30*time.Second)))
+		assert.Nil(t, VerifyWebhookSignature(body, "v0=bad,"+signature, testWebhookSecret, time.Minute, testWebhookTime))
+	})
+
+	t.Run("rejects other secrets and bodies", func(t *testing.T) {
+		signature := WebhookSignature(body, testWebhookSecret, testWebhookTime)
+
+		assert.ErrorIs(t, VerifyWebhookSignature(body, signature, "other", time.Minute, testWebhookTime), ErrWebhookSignature)
+		assert.ErrorIs(t, VerifyWebhookSignature([]byte(`{"email":"eve@example.com"}`), signature, testWebhookSecret, time.Minute, testWebhookTime), ErrWebhookSignature)
+	})
+
+	t.Run("rejects signatures outside the tolerance", func(t *testing.T) {
+		signature := WebhookSignature(body, testWebhookSecret, testWebhookTime)
+
+		assert.ErrorIs(t, VerifyWebhookSignature(body, signature, testWebhookSecret, time.Minute, testWebhookTime.Add(2*time.Minute)), ErrWebhookExpired)
+		assert.ErrorIs(t, VerifyWebhookSignature(body, signature, testWebhookSecret, time.Minute, testWebhookTime.Add(-2*time.Minute)), ErrWebhookExpired)
+	})
+
+	t.Run("rejects malformed headers", func(t *testing.T) {
+		for _, signature := range []string{"", "t=abc,v0=123", "t=1630497600000", "v0=123"} {
+			err := VerifyWebhookSignature(body, signature, testWebhookSecret, time.Minute, testWebhookTime)
+			assert.ErrorIs(t, err, ErrWebhookSignature, signature)
+		}
+	})
+}
+
+func TestWebhookHandler(t *testing.T) {
+	handler := &WebhookHandler{
+		Secret: testWebhookSecret,
+		Now: func() time.Time {
+			return testWebhookTime.Add(time.Minute)
+		},
+		GetUser: func(ctx context.Context, email string) (UserData, error) {
+			switch email {
+			case "ada@example.com":
+				return UserData{
+					Name:      "Ada",
+					Keys:      []*UserKey{{Name: "Production", ApiKey: "live_123"}},
+					Variables: map[string]interface{}{"plan": "enterprise"},
+				}, nil
+			case "broken@example.com":
+				return UserData{}, errors.New("database unavailable")
+			}
+			return UserData{}, ErrUserNotFound
+		},
+	}
+
+	serve := func(method string, body string, signature string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, "/webhook", strings.NewReader(body))
+		if signature != "" {
+			request.Header.Set("ReadMe-Signature", signature)
+		}
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	signed := func(body string) *httptest.ResponseRecorder {
+		return serve(http.MethodPost, body, WebhookSignature([]byte(body), testWebhookSecret, testWebhookTime))
+	}
+
+	t.Run("responds with the user data", func(t *testing.T) {
+		response := signed(`{"email":"ada@example.com"}`)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "application/json", response.Header().Get("content-type"))
+		assert.JSONEq(t, `{
+			"name": "Ada",
+			"email": "ada@example.com",
+			"keys": [{"name": "Production", "apiKey": "live_123"}],
+			"plan": "enterprise"
+		}`, response.Body.String())
+	})
+
+	t.Run("responds 404 for unknown users", func(t *testing.T) {
+		response := signed(`{"email":"eve@example.com"}`)
+
+		assert.Equal(t, http.StatusNotFound, response.Code)
+		assert.JSONEq(t, `{"error": "user not found"}`, response.Body.String())
+	})
+
+	t.Run("hides callback errors", func(t *testing.T) {
+		response := signed(`{"email":"broken@example.com"}`)
+
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+		assert.JSONEq(t, `{"error": "could not get user"}`, response.Body.String())
+	})
+
+	t.Run("rejects unsigned and expired requests", func(t *testing.T) {
+		body := `{"email":"ada@example.com"}`
+
+		assert.Equal(t, http.StatusUnauthorized, serve(http.MethodPost, body, "").Code)
+		assert.Equal(t, http.StatusUnauthorized, serve(http.MethodPost, body, WebhookSignature([]byte(body), "other", testWebhookTime)).Code)
+
+		response := serve(http.MethodPost, body, WebhookSignature([]byte(body), testWebhookSecret, testWebhookTime.Add(-time.Hour)))
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+		assert.JSONEq(t, `{"error": "webhook signature expired"}`, response.Body.String())
+	})
+
+	t.Run("rejects bodies without an email", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, signed(`{"name":"Ada"}`).Code)
+		assert.Equal(t, http.StatusBadRequest, signed(`not json`).Code)
+	})
+
+	t.Run("only allows POST", func(t *testing.T) {
+		response := serve(http.MethodGet, "", "")
+
+		assert.Equal(t, http.StatusMethodNotAllowed, response.Code)
+		assert.Equal(t, http.MethodPost, response.Header().Get("allow"))
+	})
+}